@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// TimelineEntry is a step in the history of an adventurer: an event which concerns the adventurer, along
+// with the adventurer's health, gold, and XP as carried in the event.
+type TimelineEntry struct {
+	BlockNumber     uint64 `json:"block_number"`
+	TransactionHash string `json:"transaction_hash,omitempty"`
+	Event           string `json:"event"`
+	Description     string `json:"description"`
+	Health          uint64 `json:"health"`
+	Gold            uint64 `json:"gold"`
+	XP              uint64 `json:"xp"`
+}
+
+// Returns the state of the adventurer that a parsed event concerns: the first adventurer state found in
+// the event, searching the event's fields level by level (as EventSubjects does). Returns false if the
+// event does not include an adventurer state.
+func EventAdventurerState(event json.RawMessage) (Game_Game_AdventurerState, bool) {
+	var state Game_Game_AdventurerState
+
+	level := []json.RawMessage{event}
+	for len(level) > 0 {
+		var nextLevel []json.RawMessage
+		for _, value := range level {
+			var fields map[string]json.RawMessage
+			unmarshalErr := json.Unmarshal(value, &fields)
+			if unmarshalErr != nil {
+				// Not an object.
+				continue
+			}
+
+			_, hasAdventurerID := fields["AdventurerId"]
+			_, hasAdventurer := fields["Adventurer"]
+			if hasAdventurerID && hasAdventurer {
+				stateErr := json.Unmarshal(value, &state)
+				if stateErr == nil {
+					return state, true
+				}
+			}
+
+			fieldNames := make([]string, 0, len(fields))
+			for fieldName := range fields {
+				fieldNames = append(fieldNames, fieldName)
+			}
+			sort.Strings(fieldNames)
+			for _, fieldName := range fieldNames {
+				nextLevel = append(nextLevel, fields[fieldName])
+			}
+		}
+		level = nextLevel
+	}
+
+	return state, false
+}
+
+// Returns a short, human-readable description of a parsed event.
+func DescribeEvent(eventName string, rawEvent json.RawMessage) (string, error) {
+	var description string
+	var unmarshalErr error
+
+	switch eventName {
+	case Event_Game_Game_StartGame:
+		var event Game_Game_StartGame
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		if event.AdventurerMeta.Name != nil {
+			description = fmt.Sprintf("Started a game as %s", ShortStringName(event.AdventurerMeta.Name))
+		}
+	case Event_Game_Game_DiscoveredGold:
+		var event Game_Game_DiscoveredGold
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		description = fmt.Sprintf("Discovered %d gold", event.Discovery.Amount)
+	case Event_Game_Game_DiscoveredHealth:
+		var event Game_Game_DiscoveredHealth
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		description = fmt.Sprintf("Discovered %d health", event.Discovery.Amount)
+	case Event_Game_Game_DiscoveredBeast:
+		var event Game_Game_DiscoveredBeast
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		description = fmt.Sprintf("Discovered %s", describeBeast(event.Id, event.BeastSpecs))
+	case Event_Game_Game_AmbushedByBeast:
+		var event Game_Game_AmbushedByBeast
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		details := event.BeastBattleDetails
+		description = fmt.Sprintf("Ambushed by %s, taking %d damage%s", describeBeast(details.Id, details.BeastSpecs), details.Damage, describeCriticalHit(details.CriticalHit))
+	case Event_Game_Game_AttackedBeast:
+		var event Game_Game_AttackedBeast
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		details := event.BeastBattleDetails
+		description = fmt.Sprintf("Attacked %s, dealing %d damage%s", describeBeast(details.Id, details.BeastSpecs), details.Damage, describeCriticalHit(details.CriticalHit))
+	case Event_Game_Game_AttackedByBeast:
+		var event Game_Game_AttackedByBeast
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		details := event.BeastBattleDetails
+		description = fmt.Sprintf("Attacked by %s, taking %d damage%s", describeBeast(details.Id, details.BeastSpecs), details.Damage, describeCriticalHit(details.CriticalHit))
+	case Event_Game_Game_SlayedBeast:
+		var event Game_Game_SlayedBeast
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		description = fmt.Sprintf("Slayed %s with %d damage%s, earning %d XP and %d gold", describeBeast(event.Id, event.BeastSpecs), event.DamageDealt, describeCriticalHit(event.CriticalHit), event.XpEarnedAdventurer, event.GoldEarned)
+	case Event_Game_Game_FleeFailed:
+		var event Game_Game_FleeFailed
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		description = fmt.Sprintf("Failed to flee from %s", describeBeast(event.FleeEvent.Id, event.FleeEvent.BeastSpecs))
+	case Event_Game_Game_FleeSucceeded:
+		var event Game_Game_FleeSucceeded
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		description = fmt.Sprintf("Fled from %s", describeBeast(event.FleeEvent.Id, event.FleeEvent.BeastSpecs))
+	case Event_Game_Game_DodgedObstacle:
+		var event Game_Game_DodgedObstacle
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		details := event.ObstacleEvent.ObstacleDetails
+		description = fmt.Sprintf("Dodged %s, earning %d XP", describeObstacle(details.Id, details.Level), details.AdventurerXpReward)
+	case Event_Game_Game_HitByObstacle:
+		var event Game_Game_HitByObstacle
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		details := event.ObstacleEvent.ObstacleDetails
+		description = fmt.Sprintf("Hit by %s, taking %d damage%s and earning %d XP", describeObstacle(details.Id, details.Level), details.DamageTaken, describeCriticalHit(details.CriticalHit), details.AdventurerXpReward)
+	case Event_Game_Game_PurchasedItems:
+		var event Game_Game_PurchasedItems
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		items := make([]string, len(event.Purchases))
+		for i, purchase := range event.Purchases {
+			items[i] = fmt.Sprintf("%s for %d gold", describeItem(purchase.Item.Id), purchase.Price)
+		}
+		description = fmt.Sprintf("Purchased %s", strings.Join(items, ", "))
+	case Event_Game_Game_PurchasedPotions:
+		var event Game_Game_PurchasedPotions
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		description = fmt.Sprintf("Purchased %d potions for %d gold, restoring %d health", event.Quantity, event.Cost, event.Health)
+	case Event_Game_Game_AdventurerLeveledUp:
+		var event Game_Game_AdventurerLeveledUp
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		description = fmt.Sprintf("Leveled up from level %d to level %d", event.PreviousLevel, event.NewLevel)
+	case Event_Game_Game_AdventurerUpgraded:
+		var event Game_Game_AdventurerUpgraded
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		var increases []string
+		for _, increase := range []struct {
+			stat  string
+			value uint64
+		}{
+			{"strength", event.StrengthIncrease},
+			{"dexterity", event.DexterityIncrease},
+			{"vitality", event.VitalityIncrease},
+			{"intelligence", event.IntelligenceIncrease},
+			{"wisdom", event.WisdomIncrease},
+			{"charisma", event.CharismaIncrease},
+		} {
+			if increase.value > 0 {
+				increases = append(increases, fmt.Sprintf("+%d %s", increase.value, increase.stat))
+			}
+		}
+		description = "Upgraded stats"
+		if len(increases) > 0 {
+			description += ": " + strings.Join(increases, ", ")
+		}
+	case Event_Game_Game_EquippedItems:
+		var event Game_Game_EquippedItems
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		description = fmt.Sprintf("Equipped %s", describeItems(event.EquippedItems))
+		if len(event.UnequippedItems) > 0 {
+			description += fmt.Sprintf(", unequipping %s", describeItems(event.UnequippedItems))
+		}
+	case Event_Game_Game_DroppedItems:
+		var event Game_Game_DroppedItems
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		description = fmt.Sprintf("Dropped %s", describeItems(event.ItemIds))
+	case Event_Game_Game_ItemsLeveledUp:
+		var event Game_Game_ItemsLeveledUp
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		items := make([]string, len(event.Items))
+		for i, item := range event.Items {
+			items[i] = fmt.Sprintf("%s from level %d to level %d", FullItemName(item.ItemId, item.Specials), item.PreviousLevel, item.NewLevel)
+		}
+		description = fmt.Sprintf("Leveled up %s", strings.Join(items, ", "))
+	case Event_Game_Game_UpgradesAvailable:
+		var event Game_Game_UpgradesAvailable
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		description = "Upgrades available"
+		if len(event.Items) > 0 {
+			description += fmt.Sprintf(", with %s in the market", describeItems(event.Items))
+		}
+	case Event_Game_Game_NewHighScore:
+		var event Game_Game_NewHighScore
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		description = fmt.Sprintf("Set a new high score, at rank %d", event.Rank)
+	case Event_Game_Game_IdleDeathPenalty:
+		var event Game_Game_IdleDeathPenalty
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		description = fmt.Sprintf("Killed by the idle death penalty after %d idle blocks", event.IdleBlocks)
+	case Event_Game_Game_AdventurerDied:
+		var event Game_Game_AdventurerDied
+		unmarshalErr = json.Unmarshal(rawEvent, &event)
+		description = "Died"
+		if event.DeathDetails.KilledByBeast != 0 {
+			description = fmt.Sprintf("Killed by %s", BeastName(event.DeathDetails.KilledByBeast))
+		} else if event.DeathDetails.KilledByObstacle != 0 {
+			description = fmt.Sprintf("Killed by %s", ObstacleName(event.DeathDetails.KilledByObstacle))
+		}
+	}
+
+	if unmarshalErr != nil {
+		return "", unmarshalErr
+	}
+	if description == "" {
+		description = strings.TrimPrefix(eventName, "game::Game::")
+	}
+	return description, nil
+}
+
+func describeBeast(beastID uint64, specs Combat_Combat_CombatSpec) string {
+	return fmt.Sprintf("%s (level %d, tier %d)", FullBeastName(beastID, specs.Specials), specs.Level, specs.Tier)
+}
+
+func describeObstacle(obstacleID, level uint64) string {
+	return fmt.Sprintf("%s (level %d)", ObstacleName(obstacleID), level)
+}
+
+func describeItem(itemID uint64) string {
+	return ItemName(itemID)
+}
+
+func describeItems(itemIDs []uint64) string {
+	if len(itemIDs) == 0 {
+		return "no items"
+	}
+	items := make([]string, len(itemIDs))
+	for i, itemID := range itemIDs {
+		items[i] = describeItem(itemID)
+	}
+	return strings.Join(items, ", ")
+}
+
+func describeCriticalHit(criticalHit Core_Bool) string {
+	if criticalHit != 0 {
+		return " (critical hit)"
+	}
+	return ""
+}
+
+// Reconstructs the history of an adventurer from a file of parsed events (as produced by the "stark
+// events" command, with retracted events already removed). Every event that concerns the adventurer (see
+// EventSubjects) is included, in the order in which the events were emitted.
+func AdventurerHistory(eventsFile io.Reader, adventurerID string) ([]TimelineEntry, error) {
+	normalizedID, normalizeErr := NormalizeAdventurerID(adventurerID)
+	if normalizeErr != nil {
+		return nil, normalizeErr
+	}
+
+	timeline := []TimelineEntry{}
+
+	scanner := bufio.NewScanner(eventsFile)
+	for scanner.Scan() {
+		var event PartialCrawledEvent
+		unmarshalErr := json.Unmarshal(scanner.Bytes(), &event)
+		if unmarshalErr != nil {
+			return timeline, unmarshalErr
+		}
+		if event.Name == EVENT_UNKNOWN || event.Name == EVENT_RETRACTED {
+			continue
+		}
+
+		eventAdventurerID, _ := EventSubjects(event.Event)
+		if eventAdventurerID != normalizedID {
+			continue
+		}
+
+		description, describeErr := DescribeEvent(event.Name, event.Event)
+		if describeErr != nil {
+			return timeline, fmt.Errorf("could not describe %s event in block %d: %w", event.Name, event.BlockNumber, describeErr)
+		}
+
+		entry := TimelineEntry{
+			BlockNumber: event.BlockNumber,
+			Event:       strings.TrimPrefix(event.Name, "game::Game::"),
+			Description: description,
+		}
+		if event.TransactionHash != nil {
+			entry.TransactionHash = event.TransactionHash.String()
+		}
+		state, ok := EventAdventurerState(event.Event)
+		if ok {
+			entry.Health = state.Adventurer.Health
+			entry.Gold = state.Adventurer.Gold
+			entry.XP = state.Adventurer.Xp
+		}
+
+		timeline = append(timeline, entry)
+	}
+
+	return timeline, scanner.Err()
+}
+
+// Writes an adventurer's history as a human-readable table.
+func WriteTimelineTable(w io.Writer, timeline []TimelineEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "BLOCK\tEVENT\tHEALTH\tGOLD\tXP\tDESCRIPTION")
+	for _, entry := range timeline {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%d\t%s\n", entry.BlockNumber, entry.Event, entry.Health, entry.Gold, entry.XP, entry.Description)
+	}
+	return tw.Flush()
+}
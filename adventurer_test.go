@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAdventurerHistory(t *testing.T) {
+	var eventsFile []byte
+	for _, line := range gameEventLines(t) {
+		eventsFile = append(append(eventsFile, line...), '\n')
+	}
+	// Events the crawler could not parse are skipped.
+	unknownLine, marshalErr := json.Marshal(CrawledEvent{Name: EVENT_UNKNOWN, Event: map[string]string{}, BlockNumber: 13})
+	if marshalErr != nil {
+		t.Fatalf("could not marshal event: %v", marshalErr)
+	}
+	eventsFile = append(append(eventsFile, unknownLine...), '\n')
+
+	expected := []TimelineEntry{
+		{BlockNumber: 1, TransactionHash: "0x0", Event: "StartGame", Description: "Started a game as loaf"},
+		{BlockNumber: 2, TransactionHash: "0x2", Event: "DiscoveredBeast", Description: "Discovered beast 0 (level 0, tier 0)"},
+		{BlockNumber: 3, TransactionHash: "0x3", Event: "SlayedBeast", Description: "Slayed beast 0 (level 3, tier 0) with 0 damage, earning 0 XP and 0 gold", XP: 4},
+		{BlockNumber: 5, TransactionHash: "0x6", Event: "AdventurerLeveledUp", Description: "Leveled up from level 2 to level 3", XP: 9},
+		{BlockNumber: 9, TransactionHash: "0xa", Event: "DiscoveredGold", Description: "Discovered 3 gold", XP: 12},
+		{BlockNumber: 10, TransactionHash: "0xb", Event: "SlayedBeast", Description: "Slayed beast 0 (level 1, tier 0) with 0 damage, earning 0 XP and 0 gold", XP: 20},
+	}
+
+	// The adventurer ID may be given in decimal or in hex.
+	for _, adventurerID := range []string{"1", "0x1"} {
+		timeline, historyErr := AdventurerHistory(bytes.NewReader(eventsFile), adventurerID)
+		if historyErr != nil {
+			t.Fatalf("could not build history of adventurer %s: %v", adventurerID, historyErr)
+		}
+		if !reflect.DeepEqual(timeline, expected) {
+			t.Fatalf("history of adventurer %s:\nexpected %+v\ngot      %+v", adventurerID, expected, timeline)
+		}
+	}
+
+	timeline, historyErr := AdventurerHistory(bytes.NewReader(eventsFile), "4")
+	if historyErr != nil || len(timeline) != 0 {
+		t.Fatalf("expected an empty history for an adventurer without events, got %+v (error: %v)", timeline, historyErr)
+	}
+
+	if _, historyErr := AdventurerHistory(bytes.NewReader(eventsFile), "adventurer"); historyErr == nil {
+		t.Fatal("expected an error for an invalid adventurer ID")
+	}
+}
+
+func TestDescribeEvent(t *testing.T) {
+	cases := []struct {
+		name     string
+		event    interface{}
+		expected string
+	}{
+		{
+			Event_Game_Game_AttackedByBeast,
+			Game_Game_AttackedByBeast{BeastBattleDetails: Game_Game_BattleDetails{Id: 1, BeastSpecs: Combat_Combat_CombatSpec{Tier: 1, Level: 4}, Damage: 7, CriticalHit: 1}},
+			"Attacked by Warlock (level 4, tier 1), taking 7 damage (critical hit)",
+		},
+		{
+			Event_Game_Game_HitByObstacle,
+			Game_Game_HitByObstacle{ObstacleEvent: Game_Game_ObstacleEvent{ObstacleDetails: Game_Game_ObstacleDetails{Id: 26, Level: 2, DamageTaken: 3, AdventurerXpReward: 1}}},
+			"Hit by Pendulum Blades (level 2), taking 3 damage and earning 1 XP",
+		},
+		{
+			Event_Game_Game_PurchasedItems,
+			Game_Game_PurchasedItems{Purchases: []Market_Market_LootWithPrice{{Item: Lootitems_Loot_Loot{Id: 42}, Price: 20}}},
+			"Purchased Katana for 20 gold",
+		},
+		{
+			Event_Game_Game_AdventurerUpgraded,
+			Game_Game_AdventurerUpgraded{StrengthIncrease: 1, WisdomIncrease: 2},
+			"Upgraded stats: +1 strength, +2 wisdom",
+		},
+		{
+			Event_Game_Game_AdventurerDied,
+			Game_Game_AdventurerDied{DeathDetails: Game_Game_DeathDetails{KilledByBeast: 75}},
+			"Killed by Skeleton",
+		},
+		{
+			// Events without a specific description are described by their name.
+			Event_Game_Game_RewardDistribution,
+			Game_Game_RewardDistribution{},
+			"RewardDistribution",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(strings.TrimPrefix(c.name, "game::Game::"), func(t *testing.T) {
+			rawEvent, marshalErr := json.Marshal(c.event)
+			if marshalErr != nil {
+				t.Fatalf("could not marshal event: %v", marshalErr)
+			}
+			description, describeErr := DescribeEvent(c.name, rawEvent)
+			if describeErr != nil {
+				t.Fatalf("could not describe event: %v", describeErr)
+			}
+			if description != c.expected {
+				t.Fatalf("expected %q, got %q", c.expected, description)
+			}
+		})
+	}
+}
+
+func TestWriteTimelineTable(t *testing.T) {
+	var buf bytes.Buffer
+	writeErr := WriteTimelineTable(&buf, []TimelineEntry{
+		{BlockNumber: 5, Event: "DiscoveredGold", Description: "Discovered 3 gold", Health: 100, Gold: 3, XP: 12},
+	})
+	if writeErr != nil {
+		t.Fatalf("could not write table: %v", writeErr)
+	}
+
+	expected := "BLOCK  EVENT           HEALTH  GOLD  XP  DESCRIPTION\n" +
+		"5      DiscoveredGold  100     3     12  Discovered 3 gold\n"
+	if buf.String() != expected {
+		t.Fatalf("expected table:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+var ErrInvalidBackfillRange error = errors.New("backfill range size must be positive")
+
+// BackfillConfig controls how a historical crawl is split up and parallelized.
+type BackfillConfig struct {
+	// Number of block ranges to fetch concurrently.
+	Workers int
+	// Number of blocks in each range.
+	RangeSize uint64
+}
+
+// BackfillRange is a range of blocks which is fetched by a single backfill worker, along with the result
+// of fetching it.
+type BackfillRange struct {
+	FromBlock         uint64
+	ToBlock           uint64
+	ContinuationToken string
+	Events            []RawEvent
+	// Hash of ToBlock, if it was needed to detect chain reorganizations.
+	ToBlockHash *felt.Felt
+	Err         error
+}
+
+// Fetches all the events in a block range, following continuation tokens until the range is exhausted. If
+// several contracts are crawled, their events are interleaved (see InterleaveEvents).
+func FetchBackfillRange(ctx context.Context, providers *ProviderPool, config CrawlerConfig, blockRange *BackfillRange, needsHash bool) {
+	if blockRange.ContinuationToken != "" {
+		// Only a crawl of a single contract (or of all contracts) is resumed from a continuation token.
+		var contractAddress string
+		if len(config.ContractAddresses) == 1 {
+			contractAddress = config.ContractAddresses[0]
+		}
+		blockRange.Events, blockRange.Err = FetchContractEvents(ctx, providers, contractAddress, blockRange.FromBlock, blockRange.ToBlock, blockRange.ContinuationToken, config.BatchSize)
+	} else {
+		blockRange.Events, blockRange.Err = FetchEvents(ctx, providers, config, blockRange.FromBlock, blockRange.ToBlock)
+	}
+	if blockRange.Err != nil {
+		return
+	}
+
+	if needsHash {
+		blockRange.Err = providers.Do(ctx, func(provider *rpc.Provider) error {
+			var err error
+			blockRange.ToBlockHash, err = BlockHash(ctx, provider, blockRange.ToBlock)
+			return err
+		})
+	}
+}
+
+// Crawls the events from the cursor's position up to config.ToBlock (or, for a continuous crawl, up to the
+// latest block with the required number of confirmations at the time the backfill starts). The blocks are
+// split into ranges of backfillConfig.RangeSize blocks, and up to backfillConfig.Workers ranges are
+// fetched concurrently.
+//
+// Batches are delivered on outChan one block range at a time, in order, so the events come out in exactly
+// the same order (and with the same indices) as from CrawlContractEvents. The cursors at range boundaries
+// point to the same blocks, but only the ranges which end within config.ReorgDepth blocks of the end of
+// the backfill fetch the hash of their last block, so earlier cursors do not remember those blocks unless
+// they emitted events. Unlike CrawlContractEvents, this function does not close outChan. It returns the
+// cursor from which a sequential crawl should continue once the backfill is complete, which is the same as
+// the cursor that CrawlContractEvents would have reached.
+func BackfillContractEvents(ctx context.Context, providers *ProviderPool, config CrawlerConfig, backfillConfig BackfillConfig, cursor CrawlCursor, outChan chan<- CrawlBatch) (CrawlCursor, error) {
+	if backfillConfig.RangeSize == 0 {
+		return cursor, ErrInvalidBackfillRange
+	}
+	if len(config.ContractAddresses) > 1 && cursor.ContinuationToken != "" {
+		return cursor, ErrPartialRangeWithMultipleContracts
+	}
+	workers := backfillConfig.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	// A resumed crawl may have recorded blocks which have since been replaced. These are dealt with before
+	// the backfill starts, exactly as the sequential crawler would deal with them.
+	if config.ReorgDepth > 0 && cursor.ContinuationToken == "" {
+		retracted, reorgErr := CheckForReorg(ctx, providers, &cursor)
+		if reorgErr != nil {
+			return cursor, reorgErr
+		}
+		if len(retracted) > 0 {
+			select {
+			case <-ctx.Done():
+				return cursor, nil
+			case outChan <- CrawlBatch{Retracted: retracted, Next: cursor.Copy()}:
+			}
+		}
+	}
+
+	backfillTo := config.ToBlock
+	if backfillTo == 0 {
+		var currentBlock uint64
+		blockErr := providers.Do(ctx, func(provider *rpc.Provider) error {
+			var err error
+			currentBlock, err = provider.BlockNumber(ctx)
+			return err
+		})
+		if blockErr != nil {
+			return cursor, blockErr
+		}
+		if currentBlock < uint64(config.Confirmations) {
+			return cursor, nil
+		}
+		backfillTo = currentBlock - uint64(config.Confirmations)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Each range is fetched in its own goroutine. The channels on which they deliver their results are
+	// queued in block order, and the queue's capacity limits the number of ranges that are fetched (or
+	// waiting to be delivered) at any one time.
+	pending := make(chan chan *BackfillRange, workers-1)
+
+	go func() {
+		defer close(pending)
+
+		nextFrom := cursor.FromBlock
+		first := true
+		for nextFrom <= backfillTo {
+			blockRange := &BackfillRange{FromBlock: nextFrom, ToBlock: nextFrom + backfillConfig.RangeSize - 1}
+			if first && cursor.ContinuationToken != "" {
+				// Completes the partially crawled range that the cursor points into.
+				blockRange.ToBlock = cursor.ToBlock
+				blockRange.ContinuationToken = cursor.ContinuationToken
+			}
+			if blockRange.ToBlock > backfillTo {
+				blockRange.ToBlock = backfillTo
+			}
+			first = false
+			nextFrom = blockRange.ToBlock + 1
+
+			// Block hashes are only needed for the ranges which end among the blocks the crawler must
+			// remember once the backfill is complete.
+			needsHash := config.ReorgDepth > 0 && blockRange.ToBlock+config.ReorgDepth > backfillTo
+
+			resultChan := make(chan *BackfillRange, 1)
+			select {
+			case <-ctx.Done():
+				return
+			case pending <- resultChan:
+			}
+
+			go func() {
+				FetchBackfillRange(ctx, providers, config, blockRange, needsHash)
+				resultChan <- blockRange
+			}()
+		}
+	}()
+
+	for resultChan := range pending {
+		var blockRange *BackfillRange
+		select {
+		case <-ctx.Done():
+			return cursor, nil
+		case blockRange = <-resultChan:
+		}
+		if blockRange.Err != nil {
+			return cursor, blockRange.Err
+		}
+
+		batch := CrawlBatch{Events: make([]IndexedEvent, len(blockRange.Events))}
+		for i, event := range blockRange.Events {
+			batch.Events[i] = IndexedEvent{
+				Event: event,
+				Index: cursor.recordEvent(event, config.ReorgDepth),
+			}
+		}
+
+		if blockRange.ToBlockHash != nil {
+			cursor.recordBlock(blockRange.ToBlock, blockRange.ToBlockHash, config.ReorgDepth)
+		} else {
+			cursor.forgetBlocks(blockRange.ToBlock, config.ReorgDepth)
+		}
+
+		cursor.FromBlock = blockRange.ToBlock + 1
+		cursor.ToBlock = config.ToBlock
+		cursor.ContinuationToken = ""
+
+		batch.Next = cursor.Copy()
+
+		select {
+		case <-ctx.Done():
+			return cursor, nil
+		case outChan <- batch:
+		}
+	}
+
+	return cursor, nil
+}
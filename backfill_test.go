@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBackfillContractEventsMatchesSequentialCrawl(t *testing.T) {
+	node, providers := newFakeStarknetNode(t, 30)
+	for blockNumber := uint64(1); blockNumber <= 25; blockNumber++ {
+		if blockNumber%4 == 0 {
+			continue
+		}
+		node.addEvent(blockNumber, blockNumber*0x10, 0x1)
+		if blockNumber%3 == 0 {
+			node.addEvent(blockNumber, blockNumber*0x10, 0x2)
+			node.addEvent(blockNumber, blockNumber*0x10+1, 0x1)
+		}
+	}
+
+	config := CrawlerConfig{
+		ContractAddresses: []string{"0x01", "0x02"},
+		RangeSize:         3,
+		HotThreshold:      1,
+		HotInterval:       time.Millisecond,
+		ColdInterval:      time.Millisecond,
+		ToBlock:           25,
+		BatchSize:         2,
+		ReorgDepth:        5,
+	}
+	start := CrawlCursor{FromBlock: 2}
+
+	sequentialChan := make(chan CrawlBatch)
+	crawlErrChan := make(chan error, 1)
+	go func() {
+		crawlErrChan <- CrawlContractEvents(context.Background(), providers, config, start, sequentialChan)
+	}()
+	var sequential []CrawlBatch
+	for batch := range sequentialChan {
+		sequential = append(sequential, batch)
+	}
+	if crawlErr := <-crawlErrChan; crawlErr != nil {
+		t.Fatalf("sequential crawl failed: %s", crawlErr.Error())
+	}
+
+	for _, workers := range []int{1, 4} {
+		backfillChan := make(chan CrawlBatch)
+		type backfillResult struct {
+			cursor CrawlCursor
+			err    error
+		}
+		resultChan := make(chan backfillResult, 1)
+		go func() {
+			defer close(backfillChan)
+			cursor, backfillErr := BackfillContractEvents(context.Background(), providers, config, BackfillConfig{Workers: workers, RangeSize: 3}, start, backfillChan)
+			resultChan <- backfillResult{cursor, backfillErr}
+		}()
+		var backfilled []CrawlBatch
+		for batch := range backfillChan {
+			backfilled = append(backfilled, batch)
+		}
+		result := <-resultChan
+		if result.err != nil {
+			t.Fatalf("backfill with %d workers failed: %s", workers, result.err.Error())
+		}
+
+		if len(backfilled) != len(sequential) {
+			t.Fatalf("backfill with %d workers: expected %d batches, got %d", workers, len(sequential), len(backfilled))
+		}
+		for i := range sequential {
+			// Ranges which end more than ReorgDepth blocks before the end of the backfill do not record
+			// the hash of their last block, so only the events and position of their cursors must match.
+			rangeEnd := sequential[i].Next.FromBlock - 1
+			if rangeEnd+config.ReorgDepth <= config.ToBlock {
+				if !reflect.DeepEqual(backfilled[i].Events, sequential[i].Events) || backfilled[i].Next.FromBlock != sequential[i].Next.FromBlock {
+					t.Errorf("backfill with %d workers: batch %d differs from the sequential crawl:\nexpected %+v\ngot %+v", workers, i, sequential[i], backfilled[i])
+				}
+				continue
+			}
+			if !reflect.DeepEqual(backfilled[i], sequential[i]) {
+				t.Errorf("backfill with %d workers: batch %d differs from the sequential crawl:\nexpected %+v\ngot %+v", workers, i, sequential[i], backfilled[i])
+			}
+		}
+		if !reflect.DeepEqual(result.cursor, sequential[len(sequential)-1].Next) {
+			t.Errorf("backfill with %d workers: expected to continue from %+v, got %+v", workers, sequential[len(sequential)-1].Next, result.cursor)
+		}
+	}
+}
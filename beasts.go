@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// Bindings for the events of the Beasts NFT contract (abis/Beasts.json), in the same form as the seer
+// bindings for the LootSurvivor contract in bindings.go.
+
+// ParseU256 parses a core::integer::u256, which is encoded as two felts: its low 128 bits, followed by its
+// high 128 bits.
+func ParseU256(parameters []*felt.Felt) (*big.Int, int, error) {
+	if len(parameters) < 2 {
+		return nil, 0, ErrIncorrectParameters
+	}
+	low := parameters[0].BigInt(big.NewInt(0))
+	high := parameters[1].BigInt(big.NewInt(0))
+	return high.Lsh(high, 128).Or(high, low), 2, nil
+}
+
+// ABI: LootSurvivorBeasts::beasts::Beasts::Transfer
+
+// ABI name for event
+var Event_LootSurvivorBeasts_Beasts_Beasts_Transfer string = "LootSurvivorBeasts::beasts::Beasts::Transfer"
+
+// Starknet hash for the event, as it appears in Starknet event logs.
+var Hash_LootSurvivorBeasts_Beasts_Beasts_Transfer string = "0099cd8bde557814842a3121e8ddfd433a539b8c9f14bf31ebf108d12e6196e9"
+
+// LootSurvivorBeasts_Beasts_Beasts_Transfer is the Go struct corresponding to the LootSurvivorBeasts::beasts::Beasts::Transfer event.
+type LootSurvivorBeasts_Beasts_Beasts_Transfer struct {
+	From    string
+	To      string
+	TokenId *big.Int
+}
+
+// ParseLootSurvivorBeasts_Beasts_Beasts_Transfer parses a LootSurvivorBeasts_Beasts_Beasts_Transfer event from a list of felts. This function returns a tuple of:
+// 1. The parsed LootSurvivorBeasts_Beasts_Beasts_Transfer struct representing the event
+// 2. The number of field elements consumed in the parse
+// 3. An error if the parse failed, nil otherwise
+func ParseLootSurvivorBeasts_Beasts_Beasts_Transfer(parameters []*felt.Felt) (LootSurvivorBeasts_Beasts_Beasts_Transfer, int, error) {
+	currentIndex := 0
+	result := LootSurvivorBeasts_Beasts_Beasts_Transfer{}
+
+	value0, consumed, err := ParseString(parameters[currentIndex:])
+	if err != nil {
+		return result, 0, err
+	}
+	result.From = value0
+	currentIndex += consumed
+
+	value1, consumed, err := ParseString(parameters[currentIndex:])
+	if err != nil {
+		return result, 0, err
+	}
+	result.To = value1
+	currentIndex += consumed
+
+	value2, consumed, err := ParseU256(parameters[currentIndex:])
+	if err != nil {
+		return result, 0, err
+	}
+	result.TokenId = value2
+	currentIndex += consumed
+
+	return result, currentIndex, nil
+}
+
+// ABI: LootSurvivorBeasts::beasts::Beasts::Approval
+
+// ABI name for event
+var Event_LootSurvivorBeasts_Beasts_Beasts_Approval string = "LootSurvivorBeasts::beasts::Beasts::Approval"
+
+// Starknet hash for the event, as it appears in Starknet event logs.
+var Hash_LootSurvivorBeasts_Beasts_Beasts_Approval string = "0134692b230b9e1ffa39098904722134159652b09c5bc41d88d6698779d228ff"
+
+// LootSurvivorBeasts_Beasts_Beasts_Approval is the Go struct corresponding to the LootSurvivorBeasts::beasts::Beasts::Approval event.
+type LootSurvivorBeasts_Beasts_Beasts_Approval struct {
+	Owner    string
+	Approved string
+	TokenId  *big.Int
+}
+
+// ParseLootSurvivorBeasts_Beasts_Beasts_Approval parses a LootSurvivorBeasts_Beasts_Beasts_Approval event from a list of felts. This function returns a tuple of:
+// 1. The parsed LootSurvivorBeasts_Beasts_Beasts_Approval struct representing the event
+// 2. The number of field elements consumed in the parse
+// 3. An error if the parse failed, nil otherwise
+func ParseLootSurvivorBeasts_Beasts_Beasts_Approval(parameters []*felt.Felt) (LootSurvivorBeasts_Beasts_Beasts_Approval, int, error) {
+	currentIndex := 0
+	result := LootSurvivorBeasts_Beasts_Beasts_Approval{}
+
+	value0, consumed, err := ParseString(parameters[currentIndex:])
+	if err != nil {
+		return result, 0, err
+	}
+	result.Owner = value0
+	currentIndex += consumed
+
+	value1, consumed, err := ParseString(parameters[currentIndex:])
+	if err != nil {
+		return result, 0, err
+	}
+	result.Approved = value1
+	currentIndex += consumed
+
+	value2, consumed, err := ParseU256(parameters[currentIndex:])
+	if err != nil {
+		return result, 0, err
+	}
+	result.TokenId = value2
+	currentIndex += consumed
+
+	return result, currentIndex, nil
+}
+
+// ABI: LootSurvivorBeasts::beasts::Beasts::ApprovalForAll
+
+// ABI name for event
+var Event_LootSurvivorBeasts_Beasts_Beasts_ApprovalForAll string = "LootSurvivorBeasts::beasts::Beasts::ApprovalForAll"
+
+// Starknet hash for the event, as it appears in Starknet event logs.
+var Hash_LootSurvivorBeasts_Beasts_Beasts_ApprovalForAll string = "0006ad9ed7b6318f1bcffefe19df9aeb40d22c36bed567e1925a5ccde0536edd"
+
+// LootSurvivorBeasts_Beasts_Beasts_ApprovalForAll is the Go struct corresponding to the LootSurvivorBeasts::beasts::Beasts::ApprovalForAll event.
+type LootSurvivorBeasts_Beasts_Beasts_ApprovalForAll struct {
+	Owner    string
+	Operator string
+	Approved Core_Bool
+}
+
+// ParseLootSurvivorBeasts_Beasts_Beasts_ApprovalForAll parses a LootSurvivorBeasts_Beasts_Beasts_ApprovalForAll event from a list of felts. This function returns a tuple of:
+// 1. The parsed LootSurvivorBeasts_Beasts_Beasts_ApprovalForAll struct representing the event
+// 2. The number of field elements consumed in the parse
+// 3. An error if the parse failed, nil otherwise
+func ParseLootSurvivorBeasts_Beasts_Beasts_ApprovalForAll(parameters []*felt.Felt) (LootSurvivorBeasts_Beasts_Beasts_ApprovalForAll, int, error) {
+	currentIndex := 0
+	result := LootSurvivorBeasts_Beasts_Beasts_ApprovalForAll{}
+
+	value0, consumed, err := ParseString(parameters[currentIndex:])
+	if err != nil {
+		return result, 0, err
+	}
+	result.Owner = value0
+	currentIndex += consumed
+
+	value1, consumed, err := ParseString(parameters[currentIndex:])
+	if err != nil {
+		return result, 0, err
+	}
+	result.Operator = value1
+	currentIndex += consumed
+
+	value2, consumed, err := ParseCore_Bool(parameters[currentIndex:])
+	if err != nil {
+		return result, 0, err
+	}
+	result.Approved = value2
+	currentIndex += consumed
+
+	return result, currentIndex, nil
+}
+
+// BeastsEventParser parses the events of the Beasts NFT contract, in the same way that EventParser parses
+// the events of the LootSurvivor contract.
+type BeastsEventParser struct {
+	Event_LootSurvivorBeasts_Beasts_Beasts_Transfer_Felt       *felt.Felt
+	Event_LootSurvivorBeasts_Beasts_Beasts_Approval_Felt       *felt.Felt
+	Event_LootSurvivorBeasts_Beasts_Beasts_ApprovalForAll_Felt *felt.Felt
+}
+
+func NewBeastsEventParser() (*BeastsEventParser, error) {
+	var feltErr error
+	parser := &BeastsEventParser{}
+	parser.Event_LootSurvivorBeasts_Beasts_Beasts_Transfer_Felt, feltErr = FeltFromHexString(Hash_LootSurvivorBeasts_Beasts_Beasts_Transfer)
+	if feltErr != nil {
+		return parser, feltErr
+	}
+	parser.Event_LootSurvivorBeasts_Beasts_Beasts_Approval_Felt, feltErr = FeltFromHexString(Hash_LootSurvivorBeasts_Beasts_Beasts_Approval)
+	if feltErr != nil {
+		return parser, feltErr
+	}
+	parser.Event_LootSurvivorBeasts_Beasts_Beasts_ApprovalForAll_Felt, feltErr = FeltFromHexString(Hash_LootSurvivorBeasts_Beasts_Beasts_ApprovalForAll)
+	if feltErr != nil {
+		return parser, feltErr
+	}
+	return parser, nil
+}
+
+// Parses an event of the Beasts contract. The ABI declares every member of these events as data, but
+// deployments of the contract built on the OpenZeppelin ERC721 component emit some members as keys
+// instead. The members are therefore parsed from the event's keys (after the selector) followed by its
+// data, which is correct for either layout.
+func (p *BeastsEventParser) Parse(event RawEvent) (ParsedEvent, error) {
+	defaultResult := ParsedEvent{Name: EVENT_UNKNOWN, Event: event}
+
+	var parameters []*felt.Felt
+	if len(event.Keys) > 1 {
+		parameters = append(parameters, event.Keys[1:]...)
+	}
+	parameters = append(parameters, event.Parameters...)
+
+	if p.Event_LootSurvivorBeasts_Beasts_Beasts_Transfer_Felt.Cmp(event.PrimaryKey) == 0 {
+		parsedEvent, _, parseErr := ParseLootSurvivorBeasts_Beasts_Beasts_Transfer(parameters)
+		if parseErr != nil {
+			return defaultResult, parseErr
+		}
+		return ParsedEvent{Name: Event_LootSurvivorBeasts_Beasts_Beasts_Transfer, Event: parsedEvent}, nil
+	}
+	if p.Event_LootSurvivorBeasts_Beasts_Beasts_Approval_Felt.Cmp(event.PrimaryKey) == 0 {
+		parsedEvent, _, parseErr := ParseLootSurvivorBeasts_Beasts_Beasts_Approval(parameters)
+		if parseErr != nil {
+			return defaultResult, parseErr
+		}
+		return ParsedEvent{Name: Event_LootSurvivorBeasts_Beasts_Beasts_Approval, Event: parsedEvent}, nil
+	}
+	if p.Event_LootSurvivorBeasts_Beasts_Beasts_ApprovalForAll_Felt.Cmp(event.PrimaryKey) == 0 {
+		parsedEvent, _, parseErr := ParseLootSurvivorBeasts_Beasts_Beasts_ApprovalForAll(parameters)
+		if parseErr != nil {
+			return defaultResult, parseErr
+		}
+		return ParsedEvent{Name: Event_LootSurvivorBeasts_Beasts_Beasts_ApprovalForAll, Event: parsedEvent}, nil
+	}
+	return defaultResult, nil
+}
+
+// BeastHolding is the current owner of a beast token, as established by the token's Transfer events.
+type BeastHolding struct {
+	TokenId *big.Int `json:"token_id"`
+	Owner   string   `json:"owner"`
+	// Block and transaction of the Transfer event which gave the token to its owner.
+	BlockNumber     uint64 `json:"block_number"`
+	TransactionHash string `json:"transaction_hash,omitempty"`
+}
+
+// Rebuilds the current ownership of each beast token by replaying the Transfer events in a file of parsed
+// events (as produced by the "stark events" command, with retracted events already removed), in the order
+// in which they were emitted. A token is minted by a transfer from the zero address and burned by a
+// transfer to it; burned tokens are not included. Holdings are returned in order of token ID.
+func BeastHolders(eventsFile io.Reader) ([]BeastHolding, error) {
+	holdings := make(map[string]BeastHolding)
+
+	scanner := bufio.NewScanner(eventsFile)
+	for scanner.Scan() {
+		var event PartialCrawledEvent
+		unmarshalErr := json.Unmarshal(scanner.Bytes(), &event)
+		if unmarshalErr != nil {
+			return nil, unmarshalErr
+		}
+		if event.Name != Event_LootSurvivorBeasts_Beasts_Beasts_Transfer {
+			continue
+		}
+
+		var transfer LootSurvivorBeasts_Beasts_Beasts_Transfer
+		transferErr := json.Unmarshal(event.Event, &transfer)
+		if transferErr != nil {
+			return nil, transferErr
+		}
+		if transfer.TokenId == nil {
+			continue
+		}
+
+		tokenKey := transfer.TokenId.String()
+		to, toErr := NormalizeAddress(transfer.To)
+		if toErr != nil {
+			return nil, toErr
+		}
+		if to == "0x0" {
+			delete(holdings, tokenKey)
+			continue
+		}
+
+		holding := BeastHolding{TokenId: transfer.TokenId, Owner: to, BlockNumber: event.BlockNumber}
+		if event.TransactionHash != nil {
+			holding.TransactionHash = event.TransactionHash.String()
+		}
+		holdings[tokenKey] = holding
+	}
+	if scanErr := scanner.Err(); scanErr != nil {
+		return nil, scanErr
+	}
+
+	result := make([]BeastHolding, 0, len(holdings))
+	for _, holding := range holdings {
+		result = append(result, holding)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TokenId.Cmp(result[j].TokenId) < 0
+	})
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// Returns the line that "stark events" writes for a transfer of a beast token.
+func transferLine(t *testing.T, blockNumber uint64, from, to string, tokenID int64) []byte {
+	t.Helper()
+	line, marshalErr := json.Marshal(CrawledEvent{
+		Name:            Event_LootSurvivorBeasts_Beasts_Beasts_Transfer,
+		Event:           LootSurvivorBeasts_Beasts_Beasts_Transfer{From: from, To: to, TokenId: big.NewInt(tokenID)},
+		BlockNumber:     blockNumber,
+		TransactionHash: new(felt.Felt).SetUint64(blockNumber * 0x10),
+	})
+	if marshalErr != nil {
+		t.Fatalf("could not marshal transfer: %s", marshalErr.Error())
+	}
+	return line
+}
+
+func TestBeastHolders(t *testing.T) {
+	zero := "0x0000000000000000000000000000000000000000000000000000000000000000"
+
+	cases := []struct {
+		name  string
+		lines [][]byte
+		// Each holding as "token_id owner block_number".
+		expected []string
+	}{
+		{
+			name:     "mint and transfer",
+			lines:    [][]byte{transferLine(t, 1, zero, "0xa", 2), transferLine(t, 2, zero, "0xa", 1), transferLine(t, 3, "0xa", "0x00b", 2)},
+			expected: []string{"1 0xa 2", "2 0xb 3"},
+		},
+		{
+			name:     "burn",
+			lines:    [][]byte{transferLine(t, 1, zero, "0xa", 1), transferLine(t, 2, zero, "0xa", 2), transferLine(t, 3, "0xa", zero, 1)},
+			expected: []string{"2 0xa 2"},
+		},
+		{
+			name:     "burn to short zero address",
+			lines:    [][]byte{transferLine(t, 1, zero, "0xa", 1), transferLine(t, 2, "0xa", "0x0", 1)},
+			expected: []string{},
+		},
+		{
+			name:     "mint after burn",
+			lines:    [][]byte{transferLine(t, 1, zero, "0xa", 1), transferLine(t, 2, "0xa", zero, 1), transferLine(t, 3, zero, "0xc", 1)},
+			expected: []string{"1 0xc 3"},
+		},
+		{
+			name:     "burn of unknown token",
+			lines:    [][]byte{transferLine(t, 1, "0xa", zero, 7), transferLine(t, 2, zero, "0xa", 1)},
+			expected: []string{"1 0xa 2"},
+		},
+		{
+			name:     "other events",
+			lines:    [][]byte{[]byte(`{"Name":"game::Game::StartGame","Event":{},"BlockNumber":1}`), transferLine(t, 2, zero, "0xa", 1)},
+			expected: []string{"1 0xa 2"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			holdings, holdersErr := BeastHolders(bytes.NewReader(bytes.Join(c.lines, []byte("\n"))))
+			if holdersErr != nil {
+				t.Fatalf("could not rebuild beast holders: %s", holdersErr.Error())
+			}
+			described := make([]string, len(holdings))
+			for i, holding := range holdings {
+				described[i] = fmt.Sprintf("%s %s %d", holding.TokenId.String(), holding.Owner, holding.BlockNumber)
+			}
+			if !reflect.DeepEqual(described, c.expected) {
+				t.Errorf("expected holdings %v, got %v", c.expected, described)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// Stat boosts granted by each item suffix, indexed by suffix (see ITEM_SUFFIXES). An equipped item grants
+// the boost of its suffix once it has unlocked the suffix (see
+// Survivor_ItemPrimitive_ItemPrimitive.SuffixUnlocked).
+var SUFFIX_STAT_BOOSTS []Survivor_Stats_Stats = []Survivor_Stats_Stats{
+	{},
+	{Strength: 3},                            // of Power
+	{Vitality: 3},                            // of Giant
+	{Strength: 2, Charisma: 1},               // of Titans
+	{Dexterity: 3},                           // of Skill
+	{Strength: 1, Dexterity: 1, Vitality: 1}, // of Perfection
+	{Intelligence: 3},                        // of Brilliance
+	{Wisdom: 3},                              // of Enlightenment
+	{Vitality: 2, Dexterity: 1},              // of Protection
+	{Strength: 2, Dexterity: 1},              // of Anger
+	{Strength: 1, Charisma: 1, Wisdom: 1},    // of Rage
+	{Vitality: 1, Charisma: 1, Intelligence: 1}, // of Fury
+	{Intelligence: 2, Wisdom: 1},                // of Vitriol
+	{Dexterity: 2, Charisma: 1},                 // of the Fox
+	{Wisdom: 2, Dexterity: 1},                   // of Detection
+	{Intelligence: 1, Wisdom: 2},                // of Reflection
+	{Charisma: 3},                               // of the Twins
+}
+
+// Returns the items that the adventurer has equipped, in slot order (weapon, chest, head, waist, foot,
+// hand, neck, ring). Empty slots have an Id of 0.
+func (adventurer Survivor_Adventurer_Adventurer) EquippedItems() []Survivor_ItemPrimitive_ItemPrimitive {
+	return []Survivor_ItemPrimitive_ItemPrimitive{
+		adventurer.Weapon,
+		adventurer.Chest,
+		adventurer.Head,
+		adventurer.Waist,
+		adventurer.Foot,
+		adventurer.Hand,
+		adventurer.Neck,
+		adventurer.Ring,
+	}
+}
+
+// Returns the sum of two sets of stats.
+func AddStats(a, b Survivor_Stats_Stats) Survivor_Stats_Stats {
+	return Survivor_Stats_Stats{
+		Strength:     a.Strength + b.Strength,
+		Dexterity:    a.Dexterity + b.Dexterity,
+		Vitality:     a.Vitality + b.Vitality,
+		Intelligence: a.Intelligence + b.Intelligence,
+		Wisdom:       a.Wisdom + b.Wisdom,
+		Charisma:     a.Charisma + b.Charisma,
+		Luck:         a.Luck + b.Luck,
+	}
+}
+
+// Returns the stat boosts that an adventurer's equipped items grant, given the specials of the items
+// (keyed by item ID). The specials of an item can be read from the contract (e.g. with
+// LootSurvivorCaller.GetWeaponSpecials) or taken from the ItemsLeveledUp event in which the item unlocked
+// its suffix. Items whose specials are not given, or which have not unlocked their suffix, grant no boost.
+func StatBoosts(adventurer Survivor_Adventurer_Adventurer, specials map[uint64]Survivor_ItemMeta_ItemSpecials) Survivor_Stats_Stats {
+	boosts := Survivor_Stats_Stats{}
+	for _, item := range adventurer.EquippedItems() {
+		if item.Id == 0 || !item.SuffixUnlocked() {
+			continue
+		}
+		itemSpecials, ok := specials[item.Id]
+		if !ok || itemSpecials.SpecialDash1 >= uint64(len(SUFFIX_STAT_BOOSTS)) {
+			continue
+		}
+		boosts = AddStats(boosts, SUFFIX_STAT_BOOSTS[itemSpecials.SpecialDash1])
+	}
+	return boosts
+}
+
+// Returns the effective stats of an adventurer: the adventurer's own stats (as get_adventurer_no_boosts
+// reports them) with the boosts of their equipped items applied (see StatBoosts). These are the stats
+// that get_adventurer reports.
+func EffectiveStats(adventurer Survivor_Adventurer_Adventurer, specials map[uint64]Survivor_ItemMeta_ItemSpecials) Survivor_Stats_Stats {
+	return AddStats(adventurer.Stats, StatBoosts(adventurer, specials))
+}
+
+// StatBoostCheck compares the effective stats of an adventurer, as computed by EffectiveStats from the
+// contract's unboosted state, with the stats that the contract itself reports.
+type StatBoostCheck struct {
+	AdventurerId string
+	BlockNumber  uint64
+	BaseStats    Survivor_Stats_Stats
+	// Specials of the equipped items, keyed by item ID.
+	Specials      map[uint64]Survivor_ItemMeta_ItemSpecials
+	Boosts        Survivor_Stats_Stats
+	ComputedStats Survivor_Stats_Stats
+	ContractStats Survivor_Stats_Stats
+	Match         bool
+}
+
+// Reads an adventurer's unboosted state (get_adventurer_no_boosts), the specials of their equipped items
+// (get_*_specials) and their boosted state (get_adventurer) from the LootSurvivor contract, all at the
+// same block, and checks that EffectiveStats reproduces the boosted stats from the rest. If blockNumber is
+// 0, the views are called at the current head of the chain.
+func CheckStatBoosts(ctx context.Context, providers *ProviderPool, contractAddress *felt.Felt, adventurerID string, blockNumber uint64) (StatBoostCheck, error) {
+	check := StatBoostCheck{BlockNumber: blockNumber, Specials: make(map[uint64]Survivor_ItemMeta_ItemSpecials)}
+
+	normalizedID, normalizeErr := NormalizeAdventurerID(adventurerID)
+	if normalizeErr != nil {
+		return check, normalizeErr
+	}
+	check.AdventurerId = normalizedID
+
+	if check.BlockNumber == 0 {
+		blockNumberErr := providers.Do(ctx, func(provider *rpc.Provider) error {
+			var err error
+			check.BlockNumber, err = provider.BlockNumber(ctx)
+			return err
+		})
+		if blockNumberErr != nil {
+			return check, blockNumberErr
+		}
+	}
+	blockID := rpc.WithBlockNumber(check.BlockNumber)
+
+	var base, boosted Survivor_Adventurer_Adventurer
+	err := providers.Do(ctx, func(provider *rpc.Provider) error {
+		caller := NewLootSurvivorCaller(provider, contractAddress)
+
+		var baseErr, boostedErr error
+		base, baseErr = caller.GetAdventurerNoBoosts(ctx, blockID, normalizedID)
+		if baseErr != nil {
+			return baseErr
+		}
+		boosted, boostedErr = caller.GetAdventurer(ctx, blockID, normalizedID)
+		if boostedErr != nil {
+			return boostedErr
+		}
+
+		specialsViews := []func(context.Context, rpc.BlockID, string) (Survivor_ItemMeta_ItemSpecials, error){
+			caller.GetWeaponSpecials,
+			caller.GetChestSpecials,
+			caller.GetHeadSpecials,
+			caller.GetWaistSpecials,
+			caller.GetFootSpecials,
+			caller.GetHandSpecials,
+			caller.GetNecklaceSpecials,
+			caller.GetRingSpecials,
+		}
+		for i, item := range base.EquippedItems() {
+			if item.Id == 0 {
+				continue
+			}
+			itemSpecials, specialsErr := specialsViews[i](ctx, blockID, normalizedID)
+			if specialsErr != nil {
+				return specialsErr
+			}
+			check.Specials[item.Id] = itemSpecials
+		}
+		return nil
+	})
+	if err != nil {
+		return check, fmt.Errorf("could not read the stats of adventurer %s: %w", normalizedID, err)
+	}
+
+	check.BaseStats = base.Stats
+	check.Boosts = StatBoosts(base, check.Specials)
+	check.ComputedStats = EffectiveStats(base, check.Specials)
+	check.ContractStats = boosted.Stats
+	check.Match = check.ComputedStats == check.ContractStats
+
+	return check, nil
+}
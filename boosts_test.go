@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+func TestStatBoosts(t *testing.T) {
+	// Greatness 15 (unlocked), 20 (unlocked) and 14 (locked).
+	adventurer := Survivor_Adventurer_Adventurer{
+		Weapon: Survivor_ItemPrimitive_ItemPrimitive{Id: 42, Xp: 225},
+		Chest:  Survivor_ItemPrimitive_ItemPrimitive{Id: 53, Xp: 400},
+		Head:   Survivor_ItemPrimitive_ItemPrimitive{Id: 70, Xp: 224},
+	}
+
+	cases := []struct {
+		name     string
+		specials map[uint64]Survivor_ItemMeta_ItemSpecials
+		expected Survivor_Stats_Stats
+	}{
+		{
+			name:     "unlocked suffixes",
+			specials: map[uint64]Survivor_ItemMeta_ItemSpecials{42: {SpecialDash1: 1}, 53: {SpecialDash1: 13}},
+			expected: Survivor_Stats_Stats{Strength: 3, Dexterity: 2, Charisma: 1},
+		},
+		{
+			name:     "locked suffix",
+			specials: map[uint64]Survivor_ItemMeta_ItemSpecials{70: {SpecialDash1: 7}},
+			expected: Survivor_Stats_Stats{},
+		},
+		{
+			name:     "unknown suffix",
+			specials: map[uint64]Survivor_ItemMeta_ItemSpecials{42: {SpecialDash1: uint64(len(SUFFIX_STAT_BOOSTS))}},
+			expected: Survivor_Stats_Stats{},
+		},
+		{
+			name:     "no specials",
+			specials: nil,
+			expected: Survivor_Stats_Stats{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if boosts := StatBoosts(adventurer, c.specials); boosts != c.expected {
+				t.Errorf("expected boosts %+v, got %+v", c.expected, boosts)
+			}
+		})
+	}
+}
+
+// statBoostsFixture holds the responses of the LootSurvivor contract's views for an adventurer at a block,
+// keyed by view function name, with each response given as the felts that starknet_call returns.
+type statBoostsFixture struct {
+	Description  string                  `json:"description"`
+	AdventurerID string                  `json:"adventurer_id"`
+	BlockNumber  uint64                  `json:"block_number"`
+	Responses    map[string][]*felt.Felt `json:"responses"`
+}
+
+func TestCheckStatBoostsFromViewResponses(t *testing.T) {
+	fixtureFiles, globErr := filepath.Glob(filepath.Join("testdata", "boosts", "*.json"))
+	if globErr != nil {
+		t.Fatal(globErr)
+	}
+	if len(fixtureFiles) == 0 {
+		t.Fatal("no view responses in testdata/boosts")
+	}
+
+	for _, fixtureFile := range fixtureFiles {
+		t.Run(filepath.Base(fixtureFile), func(t *testing.T) {
+			contents, readErr := os.ReadFile(fixtureFile)
+			if readErr != nil {
+				t.Fatal(readErr)
+			}
+			var fixture statBoostsFixture
+			if unmarshalErr := json.Unmarshal(contents, &fixture); unmarshalErr != nil {
+				t.Fatal(unmarshalErr)
+			}
+
+			node, providers := newFakeStarknetNode(t, fixture.BlockNumber)
+			node.calls = make(map[string][]*felt.Felt)
+			for functionName, response := range fixture.Responses {
+				node.calls[utils.GetSelectorFromNameFelt(functionName).String()] = response
+			}
+
+			check, checkErr := CheckStatBoosts(context.Background(), providers, new(felt.Felt).SetUint64(1), fixture.AdventurerID, fixture.BlockNumber)
+			if checkErr != nil {
+				t.Fatalf("could not check stat boosts: %s", checkErr.Error())
+			}
+			if check.Boosts == (Survivor_Stats_Stats{}) {
+				t.Errorf("expected the adventurer to have unlocked suffixes, but their items grant no boosts")
+			}
+			if !check.Match {
+				t.Errorf("computed stats %+v do not match the contract's stats %+v", check.ComputedStats, check.ContractStats)
+			}
+		})
+	}
+}
+
+// Views of the LootSurvivor contract that CheckStatBoosts calls, which are recorded into fixtures.
+var statBoostsViews []string = []string{
+	"get_adventurer", "get_adventurer_no_boosts",
+	"get_weapon_specials", "get_chest_specials", "get_head_specials", "get_waist_specials",
+	"get_foot_specials", "get_hand_specials", "get_necklace_specials", "get_ring_specials",
+}
+
+// viewRecorder forwards JSON-RPC requests to a Starknet node, and records the node's responses to
+// starknet_call requests for the views in statBoostsViews, keyed by view function name.
+type viewRecorder struct {
+	target    string
+	mu        sync.Mutex
+	selectors map[string]string
+	responses map[string][]*felt.Felt
+}
+
+func newViewRecorder(target string) *viewRecorder {
+	recorder := &viewRecorder{target: target, selectors: make(map[string]string), responses: make(map[string][]*felt.Felt)}
+	for _, view := range statBoostsViews {
+		recorder.selectors[utils.GetSelectorFromNameFelt(view).String()] = view
+	}
+	return recorder
+}
+
+func (recorder *viewRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requestBody, readErr := io.ReadAll(r.Body)
+	if readErr != nil {
+		http.Error(w, readErr.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, forwardErr := http.Post(recorder.target, "application/json", bytes.NewReader(requestBody))
+	if forwardErr != nil {
+		http.Error(w, forwardErr.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	responseBody, readErr := io.ReadAll(resp.Body)
+	if readErr != nil {
+		http.Error(w, readErr.Error(), http.StatusBadGateway)
+		return
+	}
+
+	var request struct {
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	var response struct {
+		Result []*felt.Felt `json:"result"`
+	}
+	if json.Unmarshal(requestBody, &request) == nil && request.Method == "starknet_call" && len(request.Params) > 0 {
+		var call rpc.FunctionCall
+		if json.Unmarshal(request.Params[0], &call) == nil && json.Unmarshal(responseBody, &response) == nil && response.Result != nil {
+			if view, ok := recorder.selectors[call.EntryPointSelector.String()]; ok {
+				recorder.mu.Lock()
+				recorder.responses[view] = response.Result
+				recorder.mu.Unlock()
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+	w.WriteHeader(resp.StatusCode)
+	w.Write(responseBody)
+}
+
+// Checks EffectiveStats against a deployed contract. Runs only if STARKNET_RPC_URL,
+// LOOT_SURVIVOR_CONTRACT_ADDRESS and LOOT_SURVIVOR_BOOSTS_ADVENTURER_ID (the ID of an adventurer with
+// unlocked suffixes) are set. LOOT_SURVIVOR_BOOSTS_BLOCK optionally sets the block at which to check.
+//
+// If LOOT_SURVIVOR_BOOSTS_RECORD is set, the node's responses to the contract's views are also written to
+// the file it names, as a fixture for TestCheckStatBoostsFromViewResponses (see testdata/boosts).
+func TestCheckStatBoostsLive(t *testing.T) {
+	contractAddress := os.Getenv("LOOT_SURVIVOR_CONTRACT_ADDRESS")
+	adventurerID := os.Getenv("LOOT_SURVIVOR_BOOSTS_ADVENTURER_ID")
+	if os.Getenv("STARKNET_RPC_URL") == "" || contractAddress == "" || adventurerID == "" {
+		t.Skip("STARKNET_RPC_URL, LOOT_SURVIVOR_CONTRACT_ADDRESS and LOOT_SURVIVOR_BOOSTS_ADVENTURER_ID must be set")
+	}
+
+	var blockNumber uint64
+	if rawBlock := os.Getenv("LOOT_SURVIVOR_BOOSTS_BLOCK"); rawBlock != "" {
+		var parseErr error
+		blockNumber, parseErr = strconv.ParseUint(rawBlock, 10, 64)
+		if parseErr != nil {
+			t.Fatalf("invalid LOOT_SURVIVOR_BOOSTS_BLOCK: %s", parseErr.Error())
+		}
+	}
+
+	contractFelt, contractErr := new(felt.Felt).SetString(contractAddress)
+	if contractErr != nil {
+		t.Fatalf("invalid LOOT_SURVIVOR_CONTRACT_ADDRESS: %s", contractErr.Error())
+	}
+	providerURLs := ProviderURLs(nil)
+	recordFile := os.Getenv("LOOT_SURVIVOR_BOOSTS_RECORD")
+	var recorder *viewRecorder
+	if recordFile != "" {
+		recorder = newViewRecorder(providerURLs[0])
+		proxy := httptest.NewServer(recorder)
+		defer proxy.Close()
+		providerURLs = []string{proxy.URL}
+	}
+	providers, poolErr := NewProviderPool(providerURLs, RetryConfig{MaxRetries: 3})
+	if poolErr != nil {
+		t.Fatal(poolErr)
+	}
+
+	check, checkErr := CheckStatBoosts(context.Background(), providers, contractFelt, adventurerID, blockNumber)
+	if checkErr != nil {
+		t.Fatalf("could not check stat boosts: %s", checkErr.Error())
+	}
+	if !check.Match {
+		t.Errorf("at block %d, computed stats %+v do not match the contract's stats %+v", check.BlockNumber, check.ComputedStats, check.ContractStats)
+	}
+
+	if recorder != nil {
+		fixture := statBoostsFixture{
+			Description:  fmt.Sprintf("Recorded by TestCheckStatBoostsLive from the LootSurvivor contract at %s, for adventurer %s at block %d.", contractAddress, check.AdventurerId, check.BlockNumber),
+			AdventurerID: check.AdventurerId,
+			BlockNumber:  check.BlockNumber,
+			Responses:    recorder.responses,
+		}
+		contents, marshalErr := json.MarshalIndent(fixture, "", "  ")
+		if marshalErr != nil {
+			t.Fatal(marshalErr)
+		}
+		if writeErr := os.WriteFile(recordFile, append(contents, '\n'), 0644); writeErr != nil {
+			t.Fatal(writeErr)
+		}
+	}
+}
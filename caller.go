@@ -0,0 +1,2484 @@
+// This file was generated by gencaller from abis/LootSurvivor.json.
+// gencaller command: go run ./gencaller -abi abis/LootSurvivor.json -name LootSurvivor -package main
+// Warning: Edit at your own risk. Any edits you make will NOT survive the next code generation.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// LootSurvivorCaller calls the view functions of a LootSurvivor contract through a Starknet RPC provider. Each view
+// function has a method, which takes the block at which to call the function (e.g. rpc.WithBlockTag("latest")
+// or rpc.WithBlockNumber(n)) followed by the function's arguments, and returns the function's results
+// decoded with the Parse* functions of the contract's bindings.
+type LootSurvivorCaller struct {
+	Provider        *rpc.Provider
+	ContractAddress *felt.Felt
+}
+
+func NewLootSurvivorCaller(provider *rpc.Provider, contractAddress *felt.Felt) *LootSurvivorCaller {
+	return &LootSurvivorCaller{Provider: provider, ContractAddress: contractAddress}
+}
+
+// Calls a function of the contract at the given block, and returns the raw result.
+func (caller *LootSurvivorCaller) Call(ctx context.Context, blockID rpc.BlockID, functionName string, calldata []*felt.Felt) ([]*felt.Felt, error) {
+	request := rpc.FunctionCall{
+		ContractAddress:    caller.ContractAddress,
+		EntryPointSelector: utils.GetSelectorFromNameFelt(functionName),
+		Calldata:           calldata,
+	}
+	response, callErr := caller.Provider.Call(ctx, request, blockID)
+	if callErr != nil {
+		return nil, fmt.Errorf("call to %s failed: %w", functionName, callErr)
+	}
+	return response, nil
+}
+
+// Encodes a 256-bit integer as calldata: its low 128 bits, followed by its high 128 bits.
+func EncodeU256(value *big.Int) []*felt.Felt {
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	low := new(big.Int).And(value, mask)
+	high := new(big.Int).Rsh(value, 128)
+	return []*felt.Felt{new(felt.Felt).SetBigInt(low), new(felt.Felt).SetBigInt(high)}
+}
+
+// GetAdventurer calls the get_adventurer view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetAdventurer(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_Adventurer_Adventurer, error) {
+	var result0 Survivor_Adventurer_Adventurer
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_adventurer", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_Adventurer_Adventurer(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_adventurer: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetAdventurerNoBoosts calls the get_adventurer_no_boosts view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetAdventurerNoBoosts(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_Adventurer_Adventurer, error) {
+	var result0 Survivor_Adventurer_Adventurer
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_adventurer_no_boosts", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_Adventurer_Adventurer(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_adventurer_no_boosts: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetAdventurerMeta calls the get_adventurer_meta view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetAdventurerMeta(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_AdventurerMeta_AdventurerMetadata, error) {
+	var result0 Survivor_AdventurerMeta_AdventurerMetadata
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_adventurer_meta", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_AdventurerMeta_AdventurerMetadata(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_adventurer_meta: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetHealth calls the get_health view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetHealth(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_health", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_health: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetXp calls the get_xp view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetXp(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_xp", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_xp: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetLevel calls the get_level view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetLevel(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_level", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_level: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetGold calls the get_gold view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetGold(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_gold", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_gold: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetStatUpgradesAvailable calls the get_stat_upgrades_available view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetStatUpgradesAvailable(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_stat_upgrades_available", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_stat_upgrades_available: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetLastActionBlock calls the get_last_action_block view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetLastActionBlock(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_last_action_block", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_last_action_block: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetActionsPerBlock calls the get_actions_per_block view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetActionsPerBlock(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_actions_per_block", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_actions_per_block: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetRevealBlock calls the get_reveal_block view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetRevealBlock(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_reveal_block", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_reveal_block: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// IsIdle calls the is_idle view function of the contract at the given block.
+func (caller *LootSurvivorCaller) IsIdle(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Core_Bool, uint64, error) {
+	var result0 Core_Bool
+	var result1 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, result1, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "is_idle", calldata)
+	if callErr != nil {
+		return result0, result1, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseCore_Bool(response[currentIndex:])
+	if parseErr != nil {
+		return result0, result1, fmt.Errorf("could not parse result of is_idle: %w", parseErr)
+	}
+	currentIndex += consumed
+	result1, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, result1, fmt.Errorf("could not parse result of is_idle: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, result1, nil
+}
+
+// GetStats calls the get_stats view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetStats(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_Stats_Stats, error) {
+	var result0 Survivor_Stats_Stats
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_stats", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_Stats_Stats(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_stats: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetStrength calls the get_strength view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetStrength(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_strength", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_strength: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetDexterity calls the get_dexterity view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetDexterity(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_dexterity", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_dexterity: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetVitality calls the get_vitality view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetVitality(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_vitality", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_vitality: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetIntelligence calls the get_intelligence view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetIntelligence(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_intelligence", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_intelligence: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetWisdom calls the get_wisdom view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetWisdom(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_wisdom", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_wisdom: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetCharisma calls the get_charisma view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetCharisma(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_charisma", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_charisma: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetEquippedItems calls the get_equipped_items view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetEquippedItems(ctx context.Context, blockID rpc.BlockID, adventurerId string) ([]Survivor_ItemPrimitive_ItemPrimitive, error) {
+	var result0 []Survivor_ItemPrimitive_ItemPrimitive
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_equipped_items", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseArray[Survivor_ItemPrimitive_ItemPrimitive](ParseSurvivor_ItemPrimitive_ItemPrimitive)(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_equipped_items: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetEquippedWeapon calls the get_equipped_weapon view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetEquippedWeapon(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_ItemPrimitive_ItemPrimitive, error) {
+	var result0 Survivor_ItemPrimitive_ItemPrimitive
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_equipped_weapon", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_ItemPrimitive_ItemPrimitive(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_equipped_weapon: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetEquippedChest calls the get_equipped_chest view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetEquippedChest(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_ItemPrimitive_ItemPrimitive, error) {
+	var result0 Survivor_ItemPrimitive_ItemPrimitive
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_equipped_chest", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_ItemPrimitive_ItemPrimitive(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_equipped_chest: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetEquippedHead calls the get_equipped_head view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetEquippedHead(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_ItemPrimitive_ItemPrimitive, error) {
+	var result0 Survivor_ItemPrimitive_ItemPrimitive
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_equipped_head", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_ItemPrimitive_ItemPrimitive(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_equipped_head: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetEquippedWaist calls the get_equipped_waist view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetEquippedWaist(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_ItemPrimitive_ItemPrimitive, error) {
+	var result0 Survivor_ItemPrimitive_ItemPrimitive
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_equipped_waist", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_ItemPrimitive_ItemPrimitive(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_equipped_waist: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetEquippedFoot calls the get_equipped_foot view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetEquippedFoot(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_ItemPrimitive_ItemPrimitive, error) {
+	var result0 Survivor_ItemPrimitive_ItemPrimitive
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_equipped_foot", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_ItemPrimitive_ItemPrimitive(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_equipped_foot: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetEquippedHand calls the get_equipped_hand view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetEquippedHand(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_ItemPrimitive_ItemPrimitive, error) {
+	var result0 Survivor_ItemPrimitive_ItemPrimitive
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_equipped_hand", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_ItemPrimitive_ItemPrimitive(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_equipped_hand: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetEquippedNecklace calls the get_equipped_necklace view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetEquippedNecklace(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_ItemPrimitive_ItemPrimitive, error) {
+	var result0 Survivor_ItemPrimitive_ItemPrimitive
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_equipped_necklace", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_ItemPrimitive_ItemPrimitive(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_equipped_necklace: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetEquippedRing calls the get_equipped_ring view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetEquippedRing(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_ItemPrimitive_ItemPrimitive, error) {
+	var result0 Survivor_ItemPrimitive_ItemPrimitive
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_equipped_ring", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_ItemPrimitive_ItemPrimitive(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_equipped_ring: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetWeaponGreatness calls the get_weapon_greatness view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetWeaponGreatness(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_weapon_greatness", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_weapon_greatness: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetChestGreatness calls the get_chest_greatness view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetChestGreatness(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_chest_greatness", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_chest_greatness: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetHeadGreatness calls the get_head_greatness view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetHeadGreatness(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_head_greatness", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_head_greatness: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetWaistGreatness calls the get_waist_greatness view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetWaistGreatness(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_waist_greatness", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_waist_greatness: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetFootGreatness calls the get_foot_greatness view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetFootGreatness(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_foot_greatness", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_foot_greatness: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetHandGreatness calls the get_hand_greatness view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetHandGreatness(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_hand_greatness", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_hand_greatness: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetNecklaceGreatness calls the get_necklace_greatness view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetNecklaceGreatness(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_necklace_greatness", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_necklace_greatness: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetRingGreatness calls the get_ring_greatness view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetRingGreatness(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_ring_greatness", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_ring_greatness: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetBag calls the get_bag view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetBag(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_Bag_Bag, error) {
+	var result0 Survivor_Bag_Bag
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_bag", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_Bag_Bag(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_bag: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetSpecialStorage calls the get_special_storage view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetSpecialStorage(ctx context.Context, blockID rpc.BlockID, adventurerId string, storageIndex uint64) (Survivor_ItemMeta_ItemSpecialsStorage, error) {
+	var result0 Survivor_ItemMeta_ItemSpecialsStorage
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+	calldata = append(calldata, new(felt.Felt).SetUint64(uint64(storageIndex)))
+
+	response, callErr := caller.Call(ctx, blockID, "get_special_storage", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_ItemMeta_ItemSpecialsStorage(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_special_storage: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetWeaponSpecials calls the get_weapon_specials view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetWeaponSpecials(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_ItemMeta_ItemSpecials, error) {
+	var result0 Survivor_ItemMeta_ItemSpecials
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_weapon_specials", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_ItemMeta_ItemSpecials(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_weapon_specials: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetChestSpecials calls the get_chest_specials view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetChestSpecials(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_ItemMeta_ItemSpecials, error) {
+	var result0 Survivor_ItemMeta_ItemSpecials
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_chest_specials", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_ItemMeta_ItemSpecials(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_chest_specials: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetHeadSpecials calls the get_head_specials view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetHeadSpecials(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_ItemMeta_ItemSpecials, error) {
+	var result0 Survivor_ItemMeta_ItemSpecials
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_head_specials", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_ItemMeta_ItemSpecials(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_head_specials: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetWaistSpecials calls the get_waist_specials view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetWaistSpecials(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_ItemMeta_ItemSpecials, error) {
+	var result0 Survivor_ItemMeta_ItemSpecials
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_waist_specials", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_ItemMeta_ItemSpecials(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_waist_specials: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetFootSpecials calls the get_foot_specials view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetFootSpecials(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_ItemMeta_ItemSpecials, error) {
+	var result0 Survivor_ItemMeta_ItemSpecials
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_foot_specials", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_ItemMeta_ItemSpecials(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_foot_specials: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetHandSpecials calls the get_hand_specials view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetHandSpecials(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_ItemMeta_ItemSpecials, error) {
+	var result0 Survivor_ItemMeta_ItemSpecials
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_hand_specials", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_ItemMeta_ItemSpecials(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_hand_specials: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetNecklaceSpecials calls the get_necklace_specials view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetNecklaceSpecials(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_ItemMeta_ItemSpecials, error) {
+	var result0 Survivor_ItemMeta_ItemSpecials
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_necklace_specials", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_ItemMeta_ItemSpecials(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_necklace_specials: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetRingSpecials calls the get_ring_specials view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetRingSpecials(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_ItemMeta_ItemSpecials, error) {
+	var result0 Survivor_ItemMeta_ItemSpecials
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_ring_specials", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_ItemMeta_ItemSpecials(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_ring_specials: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetItemsOnMarket calls the get_items_on_market view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetItemsOnMarket(ctx context.Context, blockID rpc.BlockID, adventurerId string) ([]uint64, error) {
+	var result0 []uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_items_on_market", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseArray[uint64](ParseUint64)(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_items_on_market: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetItemsOnMarketBySlot calls the get_items_on_market_by_slot view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetItemsOnMarketBySlot(ctx context.Context, blockID rpc.BlockID, adventurerId string, slot uint64) ([]uint64, error) {
+	var result0 []uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+	calldata = append(calldata, new(felt.Felt).SetUint64(uint64(slot)))
+
+	response, callErr := caller.Call(ctx, blockID, "get_items_on_market_by_slot", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseArray[uint64](ParseUint64)(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_items_on_market_by_slot: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetItemsOnMarketByTier calls the get_items_on_market_by_tier view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetItemsOnMarketByTier(ctx context.Context, blockID rpc.BlockID, adventurerId string, tier uint64) ([]uint64, error) {
+	var result0 []uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+	calldata = append(calldata, new(felt.Felt).SetUint64(uint64(tier)))
+
+	response, callErr := caller.Call(ctx, blockID, "get_items_on_market_by_tier", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseArray[uint64](ParseUint64)(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_items_on_market_by_tier: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetPotionPrice calls the get_potion_price view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetPotionPrice(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_potion_price", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_potion_price: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetItemPrice calls the get_item_price view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetItemPrice(ctx context.Context, blockID rpc.BlockID, adventurerId string, itemId uint64) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+	calldata = append(calldata, new(felt.Felt).SetUint64(uint64(itemId)))
+
+	response, callErr := caller.Call(ctx, blockID, "get_item_price", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_item_price: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetBaseStats calls the get_base_stats view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetBaseStats(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Survivor_Stats_Stats, error) {
+	var result0 Survivor_Stats_Stats
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_base_stats", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_Stats_Stats(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_base_stats: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetBaseStrength calls the get_base_strength view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetBaseStrength(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_base_strength", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_base_strength: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetBaseDexterity calls the get_base_dexterity view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetBaseDexterity(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_base_dexterity", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_base_dexterity: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetBaseVitality calls the get_base_vitality view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetBaseVitality(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_base_vitality", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_base_vitality: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetBaseIntelligence calls the get_base_intelligence view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetBaseIntelligence(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_base_intelligence", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_base_intelligence: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetBaseWisdom calls the get_base_wisdom view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetBaseWisdom(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_base_wisdom", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_base_wisdom: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetBaseCharisma calls the get_base_charisma view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetBaseCharisma(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_base_charisma", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_base_charisma: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetAttackingBeast calls the get_attacking_beast view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetAttackingBeast(ctx context.Context, blockID rpc.BlockID, adventurerId string) (Beasts_Beast_Beast, error) {
+	var result0 Beasts_Beast_Beast
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_attacking_beast", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseBeasts_Beast_Beast(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_attacking_beast: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetBeastHealth calls the get_beast_health view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetBeastHealth(ctx context.Context, blockID rpc.BlockID, adventurerId string) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "get_beast_health", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_beast_health: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetBeastType calls the get_beast_type view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetBeastType(ctx context.Context, blockID rpc.BlockID, beastId uint64) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	calldata = append(calldata, new(felt.Felt).SetUint64(uint64(beastId)))
+
+	response, callErr := caller.Call(ctx, blockID, "get_beast_type", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_beast_type: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetBeastTier calls the get_beast_tier view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetBeastTier(ctx context.Context, blockID rpc.BlockID, beastId uint64) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+	calldata = append(calldata, new(felt.Felt).SetUint64(uint64(beastId)))
+
+	response, callErr := caller.Call(ctx, blockID, "get_beast_tier", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_beast_tier: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// NextGameEntropyRotation calls the next_game_entropy_rotation view function of the contract at the given block.
+func (caller *LootSurvivorCaller) NextGameEntropyRotation(ctx context.Context, blockID rpc.BlockID) (string, error) {
+	var result0 string
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "next_game_entropy_rotation", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseString(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of next_game_entropy_rotation: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GameRateLimit calls the game_rate_limit view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GameRateLimit(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "game_rate_limit", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of game_rate_limit: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// StartingGold calls the starting_gold view function of the contract at the given block.
+func (caller *LootSurvivorCaller) StartingGold(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "starting_gold", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of starting_gold: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// StartingHealth calls the starting_health view function of the contract at the given block.
+func (caller *LootSurvivorCaller) StartingHealth(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "starting_health", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of starting_health: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// BasePotionPrice calls the base_potion_price view function of the contract at the given block.
+func (caller *LootSurvivorCaller) BasePotionPrice(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "base_potion_price", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of base_potion_price: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// PotionHealthAmount calls the potion_health_amount view function of the contract at the given block.
+func (caller *LootSurvivorCaller) PotionHealthAmount(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "potion_health_amount", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of potion_health_amount: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// MinimumPotionPrice calls the minimum_potion_price view function of the contract at the given block.
+func (caller *LootSurvivorCaller) MinimumPotionPrice(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "minimum_potion_price", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of minimum_potion_price: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// CharismaPotionDiscount calls the charisma_potion_discount view function of the contract at the given block.
+func (caller *LootSurvivorCaller) CharismaPotionDiscount(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "charisma_potion_discount", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of charisma_potion_discount: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// ItemsPerStatUpgrade calls the items_per_stat_upgrade view function of the contract at the given block.
+func (caller *LootSurvivorCaller) ItemsPerStatUpgrade(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "items_per_stat_upgrade", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of items_per_stat_upgrade: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// ItemTierPriceMultiplier calls the item_tier_price_multiplier view function of the contract at the given block.
+func (caller *LootSurvivorCaller) ItemTierPriceMultiplier(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "item_tier_price_multiplier", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of item_tier_price_multiplier: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// CharismaItemDiscount calls the charisma_item_discount view function of the contract at the given block.
+func (caller *LootSurvivorCaller) CharismaItemDiscount(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "charisma_item_discount", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of charisma_item_discount: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// MinimumItemPrice calls the minimum_item_price view function of the contract at the given block.
+func (caller *LootSurvivorCaller) MinimumItemPrice(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "minimum_item_price", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of minimum_item_price: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// MinimumDamageToBeasts calls the minimum_damage_to_beasts view function of the contract at the given block.
+func (caller *LootSurvivorCaller) MinimumDamageToBeasts(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "minimum_damage_to_beasts", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of minimum_damage_to_beasts: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// MinimumDamageFromBeasts calls the minimum_damage_from_beasts view function of the contract at the given block.
+func (caller *LootSurvivorCaller) MinimumDamageFromBeasts(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "minimum_damage_from_beasts", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of minimum_damage_from_beasts: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// MinimumDamageFromObstacles calls the minimum_damage_from_obstacles view function of the contract at the given block.
+func (caller *LootSurvivorCaller) MinimumDamageFromObstacles(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "minimum_damage_from_obstacles", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of minimum_damage_from_obstacles: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// ObstacleCriticalHitChance calls the obstacle_critical_hit_chance view function of the contract at the given block.
+func (caller *LootSurvivorCaller) ObstacleCriticalHitChance(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "obstacle_critical_hit_chance", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of obstacle_critical_hit_chance: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// StatUpgradesPerLevel calls the stat_upgrades_per_level view function of the contract at the given block.
+func (caller *LootSurvivorCaller) StatUpgradesPerLevel(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "stat_upgrades_per_level", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of stat_upgrades_per_level: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// BeastSpecialNameUnlockLevel calls the beast_special_name_unlock_level view function of the contract at the given block.
+func (caller *LootSurvivorCaller) BeastSpecialNameUnlockLevel(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "beast_special_name_unlock_level", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of beast_special_name_unlock_level: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// ItemXpMultiplierBeasts calls the item_xp_multiplier_beasts view function of the contract at the given block.
+func (caller *LootSurvivorCaller) ItemXpMultiplierBeasts(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "item_xp_multiplier_beasts", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of item_xp_multiplier_beasts: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// ItemXpMultiplierObstacles calls the item_xp_multiplier_obstacles view function of the contract at the given block.
+func (caller *LootSurvivorCaller) ItemXpMultiplierObstacles(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "item_xp_multiplier_obstacles", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of item_xp_multiplier_obstacles: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// StrengthBonusDamage calls the strength_bonus_damage view function of the contract at the given block.
+func (caller *LootSurvivorCaller) StrengthBonusDamage(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "strength_bonus_damage", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of strength_bonus_damage: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// OwnerOf calls the owner_of view function of the contract at the given block.
+func (caller *LootSurvivorCaller) OwnerOf(ctx context.Context, blockID rpc.BlockID, adventurerId string) (string, error) {
+	var result0 string
+
+	calldata := []*felt.Felt{}
+	adventurerIdFelt, adventurerIdErr := new(felt.Felt).SetString(adventurerId)
+	if adventurerIdErr != nil {
+		return result0, fmt.Errorf("invalid adventurer_id: %w", adventurerIdErr)
+	}
+	calldata = append(calldata, adventurerIdFelt)
+
+	response, callErr := caller.Call(ctx, blockID, "owner_of", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseString(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of owner_of: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetDaoAddress calls the get_dao_address view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetDaoAddress(ctx context.Context, blockID rpc.BlockID) (string, error) {
+	var result0 string
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "get_dao_address", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseString(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_dao_address: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetLordsAddress calls the get_lords_address view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetLordsAddress(ctx context.Context, blockID rpc.BlockID) (string, error) {
+	var result0 string
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "get_lords_address", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseString(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_lords_address: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetGameEntropy calls the get_game_entropy view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetGameEntropy(ctx context.Context, blockID rpc.BlockID) (GameEntropy_GameEntropy_GameEntropy, error) {
+	var result0 GameEntropy_GameEntropy_GameEntropy
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "get_game_entropy", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseGameEntropy_GameEntropy_GameEntropy(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_game_entropy: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetIdlePenaltyBlocks calls the get_idle_penalty_blocks view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetIdlePenaltyBlocks(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	var result0 uint64
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "get_idle_penalty_blocks", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseUint64(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_idle_penalty_blocks: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetLeaderboard calls the get_leaderboard view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetLeaderboard(ctx context.Context, blockID rpc.BlockID) (Survivor_Leaderboard_Leaderboard, error) {
+	var result0 Survivor_Leaderboard_Leaderboard
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "get_leaderboard", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseSurvivor_Leaderboard_Leaderboard(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_leaderboard: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetCostToPlay calls the get_cost_to_play view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetCostToPlay(ctx context.Context, blockID rpc.BlockID) (*big.Int, error) {
+	var result0 *big.Int
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "get_cost_to_play", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseBigInt(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_cost_to_play: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// GetGamesPlayedSnapshot calls the get_games_played_snapshot view function of the contract at the given block.
+func (caller *LootSurvivorCaller) GetGamesPlayedSnapshot(ctx context.Context, blockID rpc.BlockID) (GameSnapshot_GamesPlayedSnapshot, error) {
+	var result0 GameSnapshot_GamesPlayedSnapshot
+
+	calldata := []*felt.Felt{}
+
+	response, callErr := caller.Call(ctx, blockID, "get_games_played_snapshot", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseGameSnapshot_GamesPlayedSnapshot(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of get_games_played_snapshot: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
+
+// CanPlay calls the can_play view function of the contract at the given block.
+func (caller *LootSurvivorCaller) CanPlay(ctx context.Context, blockID rpc.BlockID, goldenTokenId *big.Int) (Core_Bool, error) {
+	var result0 Core_Bool
+
+	calldata := []*felt.Felt{}
+	calldata = append(calldata, EncodeU256(goldenTokenId)...)
+
+	response, callErr := caller.Call(ctx, blockID, "can_play", calldata)
+	if callErr != nil {
+		return result0, callErr
+	}
+
+	currentIndex := 0
+	var consumed int
+	var parseErr error
+	result0, consumed, parseErr = ParseCore_Bool(response[currentIndex:])
+	if parseErr != nil {
+		return result0, fmt.Errorf("could not parse result of can_play: %w", parseErr)
+	}
+	currentIndex += consumed
+
+	return result0, nil
+}
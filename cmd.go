@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
@@ -34,7 +35,11 @@ func CreateRootCommand() *cobra.Command {
 	findDeploymentBlockCmd := CreateFindDeploymentCmd()
 	leaderboardsCmd := CreateLeaderboardsCmd()
 	reparseCmd := CreateParseCommand()
-	rootCmd.AddCommand(completionCmd, versionCmd, starknetCmd, abiCmd, findDeploymentBlockCmd, leaderboardsCmd, reparseCmd)
+	storeCmd := CreateStoreCommand()
+	adventurerCmd := CreateAdventurerCommand()
+	beastsCmd := CreateBeastsCommand()
+	feltCmd := CreateFeltCommand()
+	rootCmd.AddCommand(completionCmd, versionCmd, starknetCmd, abiCmd, findDeploymentBlockCmd, leaderboardsCmd, reparseCmd, storeCmd, adventurerCmd, beastsCmd, feltCmd)
 
 	// By default, cobra Command objects write to stderr. We have to forcibly set them to output to
 	// stdout.
@@ -161,48 +166,61 @@ func CreateABICommand() *cobra.Command {
 }
 
 func CreateStarknetCommand() *cobra.Command {
-	var providerURL, contractAddress string
-	var timeout, fromBlock, toBlock uint64
-	var batchSize, coldInterval, hotInterval, hotThreshold, confirmations int
+	var providerURLs, contracts []string
+	var checkpointFile, outfile, storePath string
+	var timeout, fromBlock, toBlock, reorgDepth uint64
+	var batchSize, coldInterval, hotInterval, hotThreshold, confirmations, retries, retryBackoff, maxRetryBackoff, backfillWorkers int
+	var backfillRange uint64
+	var parse bool
+	var providers *ProviderPool
 
 	starkCmd := &cobra.Command{
 		Use:   "stark",
 		Short: "Interact with your Starknet RPC provider",
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			if providerURL == "" {
-				providerURLFromEnv := os.Getenv("STARKNET_RPC_URL")
-				if providerURLFromEnv == "" {
-					return errors.New("you must provide a provider URL using -p/--provider or set the STARKNET_RPC_URL environment variable")
-				}
-				providerURL = providerURLFromEnv
+			urls := ProviderURLs(providerURLs)
+			if len(urls) == 0 {
+				return errors.New("you must provide a provider URL using -p/--provider or set the STARKNET_RPC_URL environment variable")
 			}
-			return nil
+
+			retryConfig := RetryConfig{
+				MaxRetries:     retries,
+				InitialBackoff: time.Duration(retryBackoff) * time.Millisecond,
+				MaxBackoff:     time.Duration(maxRetryBackoff) * time.Millisecond,
+			}
+
+			var poolErr error
+			providers, poolErr = NewProviderPool(urls, retryConfig)
+			return poolErr
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			cmd.Help()
 		},
 	}
 
-	starkCmd.PersistentFlags().StringVarP(&providerURL, "provider", "p", "", "The URL of your Starknet RPC provider (defaults to value of STARKNET_RPC_URL environment variable)")
+	starkCmd.PersistentFlags().StringSliceVarP(&providerURLs, "provider", "p", nil, "The URL of your Starknet RPC provider (defaults to value of STARKNET_RPC_URL environment variable); specify multiple times, or as a comma-separated list, to fail over between several providers")
 	starkCmd.PersistentFlags().Uint64VarP(&timeout, "timeout", "t", 0, "The timeout for requests to your Starknet RPC provider")
+	starkCmd.PersistentFlags().IntVar(&retries, "retries", 5, "Number of times to retry a request to your Starknet RPC provider(s) which fails with a network error, a 429 response, or a 5xx response before giving up")
+	starkCmd.PersistentFlags().IntVar(&retryBackoff, "retry-backoff", 500, "Milliseconds to wait before the first retry of a failed request (doubles with each subsequent retry)")
+	starkCmd.PersistentFlags().IntVar(&maxRetryBackoff, "max-retry-backoff", 30000, "Maximum number of milliseconds to wait between retries of a failed request")
 
 	blockNumberCmd := &cobra.Command{
 		Use:   "block-number",
 		Short: "Get the current block number on your Starknet RPC provider",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, clientErr := rpc.NewClient(providerURL)
-			if clientErr != nil {
-				return clientErr
-			}
-
-			provider := rpc.NewProvider(client)
-
 			ctx := context.Background()
 			if timeout > 0 {
-				ctx, _ = context.WithDeadline(ctx, time.Now().Add(time.Duration(timeout)*time.Second))
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithDeadline(ctx, time.Now().Add(time.Duration(timeout)*time.Second))
+				defer cancel()
 			}
 
-			blockNumber, err := provider.BlockNumber(ctx)
+			var blockNumber uint64
+			err := providers.Do(ctx, func(provider *rpc.Provider) error {
+				var blockNumberErr error
+				blockNumber, blockNumberErr = provider.BlockNumber(ctx)
+				return blockNumberErr
+			})
 
 			if err != nil {
 				return err
@@ -216,19 +234,19 @@ func CreateStarknetCommand() *cobra.Command {
 		Use:   "chain-id",
 		Short: "Get the chain ID of the chain that your Starknet RPC provider is connected to",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, clientErr := rpc.NewClient(providerURL)
-			if clientErr != nil {
-				return clientErr
-			}
-
-			provider := rpc.NewProvider(client)
-
 			ctx := context.Background()
 			if timeout > 0 {
-				ctx, _ = context.WithDeadline(ctx, time.Now().Add(time.Duration(timeout)*time.Second))
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithDeadline(ctx, time.Now().Add(time.Duration(timeout)*time.Second))
+				defer cancel()
 			}
 
-			chainID, err := provider.ChainID(ctx)
+			var chainID string
+			err := providers.Do(ctx, func(provider *rpc.Provider) error {
+				var chainIDErr error
+				chainID, chainIDErr = provider.ChainID(ctx)
+				return chainIDErr
+			})
 
 			if err != nil {
 				return err
@@ -241,47 +259,234 @@ func CreateStarknetCommand() *cobra.Command {
 	eventsCmd := &cobra.Command{
 		Use:   "events",
 		Short: "Crawl events from your Starknet RPC provider",
+		Long: `Crawl events from your Starknet RPC provider
+
+Events are written one JSON object per line. If --checkpoint is specified, the position of the crawl
+(including the continuation token of a partially crawled block range) is saved to the checkpoint file
+after each chunk of events is written, and a crawl started with an existing checkpoint file resumes from
+that position.
+
+When writing to a file with --outfile, resuming from a checkpoint first discards any events which were
+written after the checkpoint was saved, so the file contains every event exactly once. When writing to
+stdout, the events of the chunk that was being written when the crawl was interrupted may be repeated.
+
+With --store, events are written to an event store (see the "store" command) instead of a file. Each chunk
+of events is written in a single transaction, the store skips events it already contains, and retraction
+records remove the retracted events from the store.
+
+The crawler remembers the hashes of the blocks it crawled within the last --reorg-depth blocks. If it finds
+that any of these blocks have been replaced by a chain reorganization, it writes a RETRACTED record for
+each event it had written from the replaced blocks, and then crawls the blocks that replaced them. The
+leaderboards commands discard retracted events, as long as they are run with the same --reorg-depth.
+
+With --backfill-workers greater than 1, the blocks up to --to (or, for a continuous crawl, up to the latest
+confirmed block at the start of the crawl) are split into ranges of --backfill-range blocks, which are
+fetched concurrently. The events are still written in block order, and the output is the same as that of a
+sequential crawl. Once the backfill is complete, a continuous crawl carries on sequentially.
+
+With --parse, the events of both the LootSurvivor contract and the Beasts NFT contract are parsed, so the
+Beasts contract can be crawled in the same way as the game contract.
+
+Several contracts can be crawled at once by specifying --contract more than once. Their events are written
+as a single stream in block order, with the events of each transaction kept together, and a single
+checkpoint covers all of them. The events of each contract in a range of up to --backfill-range blocks are
+fetched before any of them are written, so that they can be interleaved. Prefix an address with its kind,
+as in --contract game=0x... --contract beasts=0x..., to have --parse parse each contract's events only as
+events of that kind of contract.
+`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, clientErr := rpc.NewClient(providerURL)
-			if clientErr != nil {
-				return clientErr
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var checkpoint *CrawlCheckpoint
+			if checkpointFile != "" {
+				var checkpointErr error
+				checkpoint, checkpointErr = LoadCrawlCheckpoint(checkpointFile)
+				if checkpointErr != nil {
+					return checkpointErr
+				}
 			}
 
-			provider := rpc.NewProvider(client)
-			ctx := context.Background()
+			var store *EventStore
+			if storePath != "" {
+				var storeErr error
+				store, storeErr = OpenEventStore(storePath)
+				if storeErr != nil {
+					return storeErr
+				}
+				defer store.Close()
+			}
+
+			ofp := os.Stdout
+			if outfile != "" {
+				var outfileErr error
+				ofp, outfileErr = os.OpenFile(outfile, os.O_RDWR|os.O_CREATE, 0644)
+				if outfileErr != nil {
+					return outfileErr
+				}
+				defer ofp.Close()
+
+				// Discard anything written after the last checkpoint (or everything, if there is no
+				// checkpoint) so that events are not duplicated in the output.
+				var outputOffset int64
+				if checkpoint != nil {
+					outputOffset = checkpoint.OutputOffset
+				}
+				truncateErr := ofp.Truncate(outputOffset)
+				if truncateErr != nil {
+					return truncateErr
+				}
+				_, seekErr := ofp.Seek(outputOffset, io.SeekStart)
+				if seekErr != nil {
+					return seekErr
+				}
+			}
 
-			eventsChan := make(chan RawEvent)
+			contractSpecs := make([]ContractSpec, len(contracts))
+			contractAddresses := make([]string, len(contracts))
+			for i, contract := range contracts {
+				var specErr error
+				contractSpecs[i], specErr = ParseContractSpec(contract)
+				if specErr != nil {
+					return specErr
+				}
+				contractAddresses[i] = contractSpecs[i].Address
+			}
 
-			// If "fromBlock" is not specified, find the block at which the contract was deployed and
-			// use that instead.
-			if fromBlock == 0 {
-				addressFelt, parseAddressErr := FeltFromHexString(contractAddress)
-				if parseAddressErr != nil {
-					return parseAddressErr
+			var cursor CrawlCursor
+			if checkpoint != nil {
+				cursor = checkpoint.Cursor
+				// Between block ranges, the upper bound of the next range comes from this crawl's --to rather
+				// than from the crawl which wrote the checkpoint, so that a finished crawl can be extended.
+				if cursor.ContinuationToken == "" {
+					cursor.ToBlock = toBlock
 				}
-				deploymentBlock, fromBlockErr := DeploymentBlock(ctx, provider, addressFelt)
-				if fromBlockErr != nil {
-					return fromBlockErr
+			} else {
+				// If "fromBlock" is not specified, find the block at which the earliest of the contracts was
+				// deployed and use that instead.
+				if fromBlock == 0 {
+					for i, contractAddress := range contractAddresses {
+						addressFelt, parseAddressErr := FeltFromHexString(contractAddress)
+						if parseAddressErr != nil {
+							return parseAddressErr
+						}
+						var deploymentBlock uint64
+						fromBlockErr := providers.Do(ctx, func(provider *rpc.Provider) error {
+							var deploymentBlockErr error
+							deploymentBlock, deploymentBlockErr = DeploymentBlock(ctx, provider, addressFelt)
+							return deploymentBlockErr
+						})
+						if fromBlockErr != nil {
+							return fromBlockErr
+						}
+						if i == 0 || deploymentBlock < fromBlock {
+							fromBlock = deploymentBlock
+						}
+					}
 				}
-				fromBlock = deploymentBlock
+				cursor = CrawlCursor{FromBlock: fromBlock, ToBlock: toBlock}
 			}
 
-			go ContractEvents(ctx, provider, contractAddress, eventsChan, hotThreshold, time.Duration(hotInterval)*time.Millisecond, time.Duration(coldInterval)*time.Millisecond, fromBlock, toBlock, confirmations, batchSize)
+			config := CrawlerConfig{
+				ContractAddresses: contractAddresses,
+				RangeSize:         backfillRange,
+				ReorgDepth:        reorgDepth,
+				HotThreshold:      hotThreshold,
+				HotInterval:       time.Duration(hotInterval) * time.Millisecond,
+				ColdInterval:      time.Duration(coldInterval) * time.Millisecond,
+				ToBlock:           toBlock,
+				Confirmations:     confirmations,
+				BatchSize:         batchSize,
+			}
 
-			for event := range eventsChan {
-				unparsedEvent := ParsedEvent{Name: EVENT_UNKNOWN, Event: event}
-				serializedEvent, marshalErr := json.Marshal(unparsedEvent)
-				if marshalErr != nil {
-					cmd.ErrOrStderr().Write([]byte(marshalErr.Error()))
+			var parser ContractEventParser
+			if parse {
+				var newParserErr error
+				parser, newParserErr = NewContractEventParser(contractSpecs)
+				if newParserErr != nil {
+					return newParserErr
 				}
-				cmd.Println(string(serializedEvent))
 			}
 
-			return nil
+			batchesChan := make(chan CrawlBatch)
+			crawlErrChan := make(chan error, 1)
+			go func() {
+				if backfillWorkers > 1 {
+					backfillConfig := BackfillConfig{Workers: backfillWorkers, RangeSize: backfillRange}
+					var backfillErr error
+					cursor, backfillErr = BackfillContractEvents(ctx, providers, config, backfillConfig, cursor, batchesChan)
+					if backfillErr != nil {
+						close(batchesChan)
+						crawlErrChan <- backfillErr
+						return
+					}
+				}
+				crawlErrChan <- CrawlContractEvents(ctx, providers, config, cursor, batchesChan)
+			}()
+
+			newline := []byte("\n")
+
+			for batch := range batchesChan {
+				outputEvents := make([]CrawledEvent, 0, len(batch.Retracted)+len(batch.Events))
+				for _, retracted := range batch.Retracted {
+					outputEvents = append(outputEvents, RetractionRecord(retracted))
+				}
+				for _, event := range batch.Events {
+					if parse {
+						outputEvents = append(outputEvents, ParseCrawledEvent(parser, event.Event, event.Index))
+					} else {
+						outputEvents = append(outputEvents, UnparsedCrawledEvent(event.Event, event.Index))
+					}
+				}
+
+				serializedEvents := make([][]byte, len(outputEvents))
+				for i, outputEvent := range outputEvents {
+					serializedEvent, marshalErr := json.Marshal(outputEvent)
+					if marshalErr != nil {
+						return marshalErr
+					}
+					serializedEvents[i] = serializedEvent
+				}
+
+				if store != nil {
+					_, storeWriteErr := store.Write(serializedEvents)
+					if storeWriteErr != nil {
+						return storeWriteErr
+					}
+				} else {
+					for _, serializedEvent := range serializedEvents {
+						_, writeErr := ofp.Write(append(serializedEvent, newline...))
+						if writeErr != nil {
+							return writeErr
+						}
+					}
+				}
+
+				if checkpointFile != "" {
+					newCheckpoint := CrawlCheckpoint{Cursor: batch.Next}
+					if outfile != "" {
+						syncErr := ofp.Sync()
+						if syncErr != nil {
+							return syncErr
+						}
+						offset, seekErr := ofp.Seek(0, io.SeekCurrent)
+						if seekErr != nil {
+							return seekErr
+						}
+						newCheckpoint.OutputOffset = offset
+					}
+					saveErr := SaveCrawlCheckpoint(checkpointFile, newCheckpoint)
+					if saveErr != nil {
+						return saveErr
+					}
+				}
+			}
+
+			return <-crawlErrChan
 		},
 	}
 
-	eventsCmd.Flags().StringVarP(&contractAddress, "contract", "c", "", "The address of the contract from which to crawl events (if not provided, no contract constraint will be specified)")
+	eventsCmd.Flags().StringSliceVarP(&contracts, "contract", "c", nil, "The address of a contract from which to crawl events, optionally preceded by its kind (game= or beasts=); specify multiple times, or as a comma-separated list, to crawl several contracts (if not provided, no contract constraint will be specified)")
 	eventsCmd.Flags().IntVarP(&batchSize, "batch-size", "N", 100, "The number of events to fetch per batch (defaults to 100)")
 	eventsCmd.Flags().IntVar(&hotThreshold, "hot-threshold", 2, "Number of successive iterations which must return events before we consider the crawler hot")
 	eventsCmd.Flags().IntVar(&hotInterval, "hot-interval", 100, "Milliseconds at which to poll the provider for updates on the contract while the crawl is hot")
@@ -289,6 +494,14 @@ func CreateStarknetCommand() *cobra.Command {
 	eventsCmd.Flags().IntVar(&confirmations, "confirmations", 5, "Number of confirmations to wait for before considering a block canonical")
 	eventsCmd.Flags().Uint64Var(&fromBlock, "from", 0, "The block number from which to start crawling")
 	eventsCmd.Flags().Uint64Var(&toBlock, "to", 0, "The block number to which to crawl (set to 0 for continuous crawl)")
+	eventsCmd.Flags().Uint64Var(&reorgDepth, "reorg-depth", 64, "Number of recent blocks whose hashes the crawler remembers in order to detect chain reorganizations (set to 0 to disable reorganization checks)")
+	eventsCmd.Flags().StringVar(&checkpointFile, "checkpoint", "", "File in which to persist the position of the crawl after each chunk of events; if the file exists, the crawl resumes from the position it records (overriding --from)")
+	eventsCmd.Flags().StringVarP(&outfile, "outfile", "o", "", "File to write events to (defaults to stdout); when used with --checkpoint, anything written after the last checkpoint is discarded on resume, so that no events are duplicated")
+	eventsCmd.Flags().StringVarP(&storePath, "store", "s", "", "Event store (SQLite database) to write events to instead of a file; created if it does not exist")
+	eventsCmd.MarkFlagsMutuallyExclusive("outfile", "store")
+	eventsCmd.Flags().IntVar(&backfillWorkers, "backfill-workers", 0, "Number of historical block ranges to fetch concurrently (set to 0 or 1 to crawl sequentially)")
+	eventsCmd.Flags().Uint64Var(&backfillRange, "backfill-range", 1000, "Number of blocks in each range fetched by a backfill worker (also the number of blocks fetched at a time by a sequential crawl of several contracts)")
+	eventsCmd.Flags().BoolVar(&parse, "parse", false, "Set this option to parse events as they are crawled (events which fail to parse are output as UNKNOWN, with the reason in ParseError)")
 
 	starkCmd.AddCommand(blockNumberCmd, chainIDCmd, eventsCmd)
 
@@ -351,15 +564,115 @@ func CreateFindDeploymentCmd() *cobra.Command {
 }
 
 func CreateLeaderboardsCmd() *cobra.Command {
-	var infile, outfile, leaderboardID, accessToken string
+	var infile, outfile, leaderboardID, accessToken, storePath, stateFile, scoringFile, aggregateBy string
+	var fromTime, toTime, blockTimestampsFile, seasonsFile, sinkKind, sinkTarget string
+	var providerURLs, tieBreakers []string
 	var push bool
+	var pushChunkSize, pushRetries int
+	var pushTimeout time.Duration
+	var pushConfig PushConfig
+	var reorgDepth, storeFromBlock, fromBlock, toBlock uint64
+	var window TimeWindow
+	var seasons SeasonsConfig
 
 	leaderboardsCmd := &cobra.Command{
 		Use:   "leaderboards",
 		Short: "Generates Loot Survivor leaderboards and can push them to the Moonstream Leaderboards API",
+		Long: `Generates Loot Survivor leaderboards and can push them to the Moonstream Leaderboards API
+
+With --push, the leaderboard is pushed to a sink, which is chosen with --sink:
+  moonstream  The Moonstream Leaderboards API (the default). --sink-target sets the base URL of the API
+              (defaults to the value of the MOONSTREAM_API_URL environment variable, or
+              ` + MOONSTREAM_API_URL + `).
+  csv         A CSV file, given by --sink-target, with one row per score. The CSV is only written to
+              stdout ("-") if the leaderboard itself is written to a file with -o/--outfile.
+  sql         The leaderboard_scores table of a database, into which the scores are upserted.
+              --sink-target is either the path of a SQLite database or a postgres:// URL.
+  webhook     A URL, given by --sink-target, to which the leaderboard is posted as JSON, in the form
+              {"leaderboard_id": ..., "overwrite": true, "scores": [...]}. If an access token is
+              provided, it is sent as a bearer token.
+
+By default, a leaderboard includes the events from all crawled blocks. The --from-block/--to-block and
+--from-time/--to-time options restrict it to a window of blocks. Both ends of the window are inclusive,
+except for --to-time: the window contains the blocks with timestamps at or after --from-time and before
+--to-time. Times are given in RFC 3339 format (e.g. 2024-01-01T00:00:00Z) or as Unix timestamps in
+seconds. Times are resolved to blocks using the block timestamps from your Starknet RPC provider
+(-p/--provider). With --block-timestamps, the timestamps are cached in a file so that later runs do not
+need to fetch them again.
+
+StartGame events from before a window are still read, as they name the adventurers in the leaderboard.
+If a --scoring file scores StartGame events, these also count towards the total leaderboard.
+
+With --seasons, a leaderboard is generated for each window (season) listed in a YAML or JSON file, in a
+single pass over the events:
+
+seasons:
+  - name: week-1
+    from_time: 2024-01-01T00:00:00Z
+    to_time: 2024-01-08T00:00:00Z
+    leaderboard_id: 00000000-0000-0000-0000-000000000000
+  - name: genesis
+    from_block: 400000
+    to_block: 410000
+
+The output is then a JSON object mapping the name of each season to its leaderboard. When pushing, each
+season's leaderboard is pushed to the leaderboard with its leaderboard_id.
+
+Leaderboards are sorted by score, from highest to lowest, and each entrant's position is given by their
+"rank". Entrants with equal scores share a rank, unless they are separated by --tie-breakers:
+  earliest  The entrant who reached their score in an earlier block ranks higher.
+  xp        The entrant with more XP ranks higher.
+  level     The entrant with the higher level ranks higher (unlike xp, this leaves entrants of the same
+            level to the next tie-breaker).
+  id        The entrant with the lower adventurer ID (or, for leaderboards of owners, address) ranks
+            higher.
+Entrants who share a rank are listed in order of adventurer ID (or, for leaderboards of owners, address).
+`,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			tieBreakersErr := ValidateTieBreakers(tieBreakers)
+			if tieBreakersErr != nil {
+				return tieBreakersErr
+			}
+
+			var fromTimeErr, toTimeErr error
+			window = TimeWindow{FromBlock: fromBlock, ToBlock: toBlock}
+			window.FromTime, fromTimeErr = ParseWindowTime(fromTime)
+			if fromTimeErr != nil {
+				return fromTimeErr
+			}
+			window.ToTime, toTimeErr = ParseWindowTime(toTime)
+			if toTimeErr != nil {
+				return toTimeErr
+			}
+			windowErr := window.Validate()
+			if windowErr != nil {
+				return windowErr
+			}
+
+			if seasonsFile != "" {
+				if window != (TimeWindow{}) {
+					return errors.New("--seasons cannot be combined with --from-block, --to-block, --from-time or --to-time")
+				}
+				var seasonsErr error
+				seasons, seasonsErr = LoadSeasonsConfig(seasonsFile)
+				if seasonsErr != nil {
+					return seasonsErr
+				}
+			}
+
 			if push {
-				if leaderboardID == "" {
+				pushConfig = DefaultPushConfig()
+				pushConfig.ChunkSize = pushChunkSize
+				pushConfig.Retry.MaxRetries = pushRetries
+				pushConfig.Timeout = pushTimeout
+
+				if seasonsFile != "" {
+					for _, season := range seasons.Seasons {
+						if season.LeaderboardID == "" {
+							return fmt.Errorf("when pushing, every season must specify a leaderboard_id (season %s does not)", season.Name)
+						}
+					}
+				} else if leaderboardID == "" {
 					leaderboardIDFromEnv := os.Getenv("MOONSTREAM_LEADERBOARD_ID")
 					if leaderboardIDFromEnv == "" {
 						return errors.New("when pushing, you must provide a leaderboard ID using -l/--leaderboard-id or set the MOONSTREAM_LEADERBOARD_ID environment variable")
@@ -367,11 +680,16 @@ func CreateLeaderboardsCmd() *cobra.Command {
 					leaderboardID = leaderboardIDFromEnv
 				}
 				if accessToken == "" {
-					accessTokenFromEnv := os.Getenv("MOONSTREAM_ACCESS_TOKEN")
-					if accessTokenFromEnv == "" {
+					accessToken = os.Getenv("MOONSTREAM_ACCESS_TOKEN")
+					if accessToken == "" && sinkKind == SINK_MOONSTREAM {
 						return errors.New("when pushing, you must provide an access token using -t/--access-token or set the MOONSTREAM_ACCESS_TOKEN environment variable")
 					}
-					accessToken = accessTokenFromEnv
+				}
+				if sinkTarget == "" && sinkKind == SINK_MOONSTREAM {
+					sinkTarget = os.Getenv("MOONSTREAM_API_URL")
+				}
+				if sinkKind == SINK_CSV && sinkTarget == "-" && outfile == "" {
+					return errors.New("the csv sink can only write to stdout if the leaderboard is written to a file (use -o/--outfile)")
 				}
 			}
 			return nil
@@ -381,34 +699,361 @@ func CreateLeaderboardsCmd() *cobra.Command {
 		},
 	}
 	leaderboardsCmd.PersistentFlags().StringVarP(&infile, "infile", "i", "", "File containing crawled events from which to build the leaderboard (as produced by the \"loot-survivor stark events\" command, defaults to stdin)")
+	leaderboardsCmd.PersistentFlags().StringVarP(&storePath, "store", "s", "", "Event store (as written by \"stark events --store\") from which to build the leaderboard, instead of --infile")
 	leaderboardsCmd.PersistentFlags().StringVarP(&outfile, "outfile", "o", "", "File to write leaderboard to (defaults to stdout)")
-	leaderboardsCmd.PersistentFlags().BoolVar(&push, "push", false, "Set this option to pus the leaderboard to the Moonstream Leaderboard API")
-	leaderboardsCmd.PersistentFlags().StringVarP(&leaderboardID, "leaderboard-id", "l", "", "Leaderboard ID for the Moonstream Leaderboard (look up or generate at https://moonstream.to, defaults to value of MOONSTREAM_LEADERBOARD_ID environment variable)")
+	leaderboardsCmd.PersistentFlags().BoolVar(&push, "push", false, "Set this option to push the leaderboard to the sink (by default, the Moonstream Leaderboard API)")
+	leaderboardsCmd.PersistentFlags().StringVar(&sinkKind, "sink", SINK_MOONSTREAM, "Sink to which to push the leaderboard: \"moonstream\", \"csv\", \"sql\", or \"webhook\"")
+	leaderboardsCmd.PersistentFlags().StringVar(&sinkTarget, "sink-target", "", "Where the sink writes the leaderboard: the Moonstream API URL, a CSV file, a SQLite path or postgres:// URL, or a webhook URL (see --help)")
+	leaderboardsCmd.PersistentFlags().IntVar(&pushChunkSize, "push-chunk-size", DefaultPushConfig().ChunkSize, "Maximum number of scores to push in a single request (larger leaderboards are pushed in several requests, of which only the first overwrites the existing scores)")
+	leaderboardsCmd.PersistentFlags().IntVar(&pushRetries, "push-retries", DefaultPushConfig().Retry.MaxRetries, "Number of times to retry a push which fails with a network error, a 429 response, or a 5xx response")
+	leaderboardsCmd.PersistentFlags().DurationVar(&pushTimeout, "push-timeout", DefaultPushConfig().Timeout, "Time after which a push request which has not completed is abandoned and retried")
+	leaderboardsCmd.PersistentFlags().StringVarP(&leaderboardID, "leaderboard-id", "l", "", "Leaderboard ID for the Moonstream Leaderboard (look up or generate at https://moonstream.to, defaults to value of MOONSTREAM_LEADERBOARD_ID environment variable); other sinks record the leaderboard under this ID")
+	leaderboardsCmd.PersistentFlags().Uint64Var(&reorgDepth, "reorg-depth", 64, "The --reorg-depth with which the events were crawled (retracted events must refer to one of this many preceding blocks)")
 	leaderboardsCmd.PersistentFlags().StringVarP(&accessToken, "access-token", "t", "", "Access token for Moonstream API (get from https://moonstream.to, defaults to value of MOONSTREAM_ACCESS_TOKEN environment variable)")
+	leaderboardsCmd.PersistentFlags().StringSliceVar(&tieBreakers, "tie-breakers", nil, "How to order entrants with equal scores, as a comma-separated list of tie-breakers applied in turn: \"earliest\" (first to reach the score), \"xp\" (higher XP), \"level\" (higher level), or \"id\" (lower adventurer ID or owner address)")
+	leaderboardsCmd.PersistentFlags().Uint64Var(&fromBlock, "from-block", 0, "Only include events from this block onwards")
+	leaderboardsCmd.PersistentFlags().Uint64Var(&toBlock, "to-block", 0, "Only include events up to and including this block")
+	leaderboardsCmd.PersistentFlags().StringVar(&fromTime, "from-time", "", "Only include events from blocks with timestamps at or after this time (RFC 3339 or Unix seconds)")
+	leaderboardsCmd.PersistentFlags().StringVar(&toTime, "to-time", "", "Only include events from blocks with timestamps before this time (RFC 3339 or Unix seconds)")
+	leaderboardsCmd.PersistentFlags().StringSliceVarP(&providerURLs, "provider", "p", nil, "The URL of the Starknet RPC provider from which to fetch block timestamps, when resolving times to blocks (defaults to value of STARKNET_RPC_URL environment variable)")
+	leaderboardsCmd.PersistentFlags().StringVar(&blockTimestampsFile, "block-timestamps", "", "File in which to cache block timestamps, when resolving times to blocks (created if it does not exist)")
+	leaderboardsCmd.PersistentFlags().StringVar(&seasonsFile, "seasons", "", "YAML or JSON file listing windows (seasons) for each of which to generate a leaderboard")
+
+	// resolveWindows resolves the given windows to the ranges of blocks they cover. Block timestamps are
+	// only fetched (or read from the --block-timestamps cache) if one of the windows is bounded by time.
+	resolveWindows := func(windows []TimeWindow) ([]BlockWindow, error) {
+		result := make([]BlockWindow, len(windows))
+		var resolver *BlockTimeResolver
+		for i, timeWindow := range windows {
+			if !timeWindow.NeedsTimestamps() {
+				result[i] = BlockWindow{FromBlock: timeWindow.FromBlock, ToBlock: timeWindow.ToBlock}
+				continue
+			}
+
+			if resolver == nil {
+				providers, poolErr := NewProviderPool(ProviderURLs(providerURLs), RetryConfig{MaxRetries: 5, InitialBackoff: 500 * time.Millisecond, MaxBackoff: 30 * time.Second})
+				if poolErr != nil {
+					return nil, fmt.Errorf("resolving times to blocks requires a Starknet RPC provider (use -p/--provider or set the STARKNET_RPC_URL environment variable): %w", poolErr)
+				}
+				resolver = &BlockTimeResolver{Providers: providers, Index: &BlockTimestampIndex{Timestamps: make(map[uint64]uint64)}}
+				if blockTimestampsFile != "" {
+					var indexErr error
+					resolver.Index, indexErr = LoadBlockTimestampIndex(blockTimestampsFile)
+					if indexErr != nil {
+						return nil, indexErr
+					}
+				}
+			}
+
+			var resolveErr error
+			result[i], resolveErr = resolver.Resolve(context.Background(), timeWindow)
+			if resolveErr != nil {
+				return nil, resolveErr
+			}
+		}
+
+		if resolver != nil && blockTimestampsFile != "" {
+			saveErr := SaveBlockTimestampIndex(blockTimestampsFile, resolver.Index)
+			if saveErr != nil {
+				return nil, saveErr
+			}
+		}
+		return result, nil
+	}
+
+	// runLeaderboard creates a RunE function which builds a leaderboard using the given generator and
+	// writes it to the outfile (and, if requested, pushes it to the sink). When
+	// reading from an event store, only the events with the names returned by eventNames are read. With
+	// --seasons, the generator is run once for each season, concurrently.
+	runLeaderboard := func(generator func(io.Reader) ([]LeaderboardScore, error), eventNames func() []string) func(cmd *cobra.Command, args []string) error {
+		return func(cmd *cobra.Command, args []string) (err error) {
+			var sink LeaderboardSink
+			if push {
+				var sinkErr error
+				sink, sinkErr = NewLeaderboardSink(sinkKind, sinkTarget, accessToken, pushConfig)
+				if sinkErr != nil {
+					return sinkErr
+				}
+				defer func() {
+					closeErr := sink.Close()
+					if err == nil {
+						err = closeErr
+					}
+				}()
+			}
+
+			windows := []TimeWindow{window}
+			if seasonsFile != "" {
+				windows = make([]TimeWindow, len(seasons.Seasons))
+				for i, season := range seasons.Seasons {
+					// Seasons were validated when they were loaded.
+					windows[i], _ = season.Window()
+				}
+			}
+			if stateFile != "" && (seasonsFile != "" || window != (TimeWindow{})) {
+				return errors.New("--state cannot be combined with --seasons, --from-block, --to-block, --from-time or --to-time")
+			}
+
+			blockWindows, resolveErr := resolveWindows(windows)
+			if resolveErr != nil {
+				return resolveErr
+			}
+
+			var events io.Reader
+			if storePath != "" {
+				store, storeErr := OpenEventStore(storePath)
+				if storeErr != nil {
+					return storeErr
+				}
+				defer store.Close()
+
+				// The store has already removed any retracted events. The events from the blocks before the
+				// windows are still read, as they include the WindowContextEvents.
+				query := EventQuery{Names: eventNames(), FromBlock: storeFromBlock}
+				for i, blockWindow := range blockWindows {
+					if i == 0 || (query.ToBlock != 0 && (blockWindow.ToBlock == 0 || blockWindow.ToBlock > query.ToBlock)) {
+						query.ToBlock = blockWindow.ToBlock
+					}
+				}
+				events = store.Reader(query)
+			} else {
+				ifp := os.Stdin
+				var infileErr error
+				if infile != "" && infile != "-" {
+					ifp, infileErr = os.Open(infile)
+					if infileErr != nil {
+						return infileErr
+					}
+					defer ifp.Close()
+				}
+				events = WithoutRetractedEvents(ifp, reorgDepth)
+			}
+
+			ofp := os.Stdout
+			var outfileErr error
+			if outfile != "" {
+				ofp, outfileErr = os.Create(outfile)
+				if outfileErr != nil {
+					return outfileErr
+				}
+				defer ofp.Close()
+			}
+			outputEncoder := json.NewEncoder(ofp)
+
+			if seasonsFile == "" {
+				if blockWindows[0] != (BlockWindow{}) {
+					events = WithinBlocks(events, blockWindows[0])
+				}
 
+				leaderboard, leaderboardErr := generator(events)
+				if leaderboardErr != nil {
+					return leaderboardErr
+				}
+				RankLeaderboard(leaderboard, tieBreakers)
+
+				outputEncoder.Encode(leaderboard)
+
+				if push {
+					pushErr := sink.Push(leaderboardID, leaderboard, true)
+					if pushErr != nil {
+						return pushErr
+					}
+				}
+				return nil
+			}
+
+			leaderboards := make([][]LeaderboardScore, len(blockWindows))
+			leaderboardErrs := make([]error, len(blockWindows))
+			var wg sync.WaitGroup
+			for i, seasonEvents := range SplitByBlocks(events, blockWindows) {
+				wg.Add(1)
+				go func(i int, seasonEvents *io.PipeReader) {
+					defer wg.Done()
+					leaderboards[i], leaderboardErrs[i] = generator(seasonEvents)
+					// Stops any further events from being sent to this season, whether or not the
+					// generator read them all.
+					seasonEvents.Close()
+				}(i, seasonEvents)
+			}
+			wg.Wait()
+
+			output := make(map[string][]LeaderboardScore)
+			for i, season := range seasons.Seasons {
+				if leaderboardErrs[i] != nil {
+					return fmt.Errorf("could not generate leaderboard for season %s: %w", season.Name, leaderboardErrs[i])
+				}
+				RankLeaderboard(leaderboards[i], tieBreakers)
+				output[season.Name] = leaderboards[i]
+			}
+			outputEncoder.Encode(output)
+
+			if push {
+				for i, season := range seasons.Seasons {
+					pushErr := sink.Push(season.LeaderboardID, leaderboards[i], true)
+					if pushErr != nil {
+						return fmt.Errorf("could not push leaderboard for season %s: %w", season.Name, pushErr)
+					}
+				}
+			}
+			return nil
+		}
+	}
+
+	var totalScoring TotalLeaderboardScoring
+	var totalState *LootSurvivorLeaderboardState
 	totalCmd := &cobra.Command{
 		Use:   "total",
 		Short: "Leaderboard of all player events in Loot Survivor",
 		Long: `Leaderboard of all player events in Loot Survivor
 
-NOTE: This is a leaderboard of adventurers, not their owners.
+NOTE: By default, this is a leaderboard of adventurers, not their owners. With --aggregate-by owner, it
+ranks owners instead: each owner's subscores and score are the totals over the adventurers they own, and
+their "points_data" also reports their number of adventurers ("Adventurers") and the best score of any
+one of them ("BestAdventurerScore").
 
 This leaderboard awards a number of points to each event that an adventurer could be subject to. From
 these points, it calculates a total Loot Survivor score for each adventurer. The leaderboard also reports
 the individual event scores for each adventurer in the "points_data" field.
 
+The points for each event can be configured with a YAML or JSON scoring file (--scoring). The file maps
+event names to weights, and can multiply the weight of an event by fields of the event's payload:
+
+events:
+  game::Game::SlayedBeast:
+    weight: 10
+    multipliers:
+      # Multiplies the weight by the beast's level.
+      - field: BeastSpecs.Level
+      # Multiplies the weight by 5 for tier 1 beasts, 3 for tier 2 beasts, and 1 for all other beasts.
+      - field: BeastSpecs.Tier
+        values: {1: 5, 2: 3}
+        default: 1
+  game::Game::DodgedObstacle:
+    weight: 9
+
+Only the events listed in the scoring file are scored.
+
 The leaderboard also lists the active owner for each adventurer, defined as the account that last used
 the adventurer in a game session.
+
+With --state, the leaderboard is built incrementally. The aggregation state of the leaderboard is saved to
+the state file, together with the last block whose events it includes, and later runs only fold in the
+events from subsequent blocks. Events from the last --reorg-depth blocks are not saved in the state, as
+they could still be retracted, and neither are the events of the latest block, which a crawl may not have
+finished writing. The result is the same as building the leaderboard from all events.
 `,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ifp := os.Stdin
-			var infileErr error
-			if infile != "" && infile != "-" {
-				ifp, infileErr = os.Open(infile)
-				if infileErr != nil {
-					return infileErr
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if aggregateBy != AGGREGATE_BY_ADVENTURER && aggregateBy != AGGREGATE_BY_OWNER {
+				return fmt.Errorf("%w: %s", ErrInvalidAggregation, aggregateBy)
+			}
+
+			totalScoring = DefaultTotalLeaderboardScoring()
+			if scoringFile != "" {
+				var scoringErr error
+				totalScoring, scoringErr = LoadTotalLeaderboardScoring(scoringFile)
+				if scoringErr != nil {
+					return scoringErr
 				}
-				defer ifp.Close()
+			}
+
+			if stateFile == "" {
+				return nil
+			}
+			var stateErr error
+			totalState, stateErr = LoadLootSurvivorLeaderboardState(stateFile, totalScoring)
+			if stateErr != nil {
+				return stateErr
+			}
+			storeFromBlock = totalState.LastBlock + 1
+			return nil
+		},
+		RunE: runLeaderboard(func(events io.Reader) ([]LeaderboardScore, error) {
+			if totalState == nil {
+				return LootSurvivorLeaderboard(events, totalScoring, aggregateBy)
+			}
+
+			leaderboard, leaderboardErr := IncrementalLootSurvivorLeaderboard(totalState, events, reorgDepth, aggregateBy)
+			if leaderboardErr != nil {
+				return leaderboard, leaderboardErr
+			}
+			return leaderboard, SaveLootSurvivorLeaderboardState(stateFile, totalState)
+		}, func() []string {
+			return append(totalScoring.EventNames(), Event_Game_Game_StartGame)
+		}),
+	}
+	totalCmd.Flags().StringVar(&stateFile, "state", "", "File in which to persist the aggregation state of the leaderboard, so that later runs only need to process new events (created if it does not exist)")
+	totalCmd.Flags().StringVar(&aggregateBy, "aggregate-by", AGGREGATE_BY_ADVENTURER, "Whether to rank adventurers (\"adventurer\") or their owners (\"owner\")")
+	totalCmd.Flags().StringVar(&scoringFile, "scoring", "", "YAML or JSON file which configures the points awarded for each event (defaults to the built-in scoring)")
+
+	beastSlayersCmd := &cobra.Command{
+		Use:   "beast-slayers",
+		Short: "Leaderboard of adventurers by the beasts they have slain",
+		Long: `Leaderboard of adventurers by the beasts they have slain
+
+NOTE: This is a leaderboard of adventurers, not their owners.
+
+Each beast that an adventurer slays awards them the beast's level multiplied by a factor that depends on
+the beast's tier - tier 1 beasts are worth 5 times their level, down to tier 5 beasts which are worth
+their level. The "points_data" field reports the number of beasts slain, the level of the strongest
+beast slain, and the number of beasts slain of each tier.
+`,
+		RunE: runLeaderboard(BeastSlayersLeaderboard, func() []string { return BeastSlayersLeaderboardEvents }),
+	}
+
+	artfulDodgersCmd := &cobra.Command{
+		Use:   "artful-dodgers",
+		Short: "Leaderboard of adventurers by how well they avoid obstacles",
+		Long: `Leaderboard of adventurers by how well they avoid obstacles
+
+NOTE: This is a leaderboard of adventurers, not their owners.
+
+An adventurer's score is the number of obstacles they have dodged less the number of obstacles that have
+hit them. The "points_data" field reports both counts, as well as the percentage of obstacles that the
+adventurer dodged.
+`,
+		RunE: runLeaderboard(ArtfulDodgersLeaderboard, func() []string { return ArtfulDodgersLeaderboardEvents }),
+	}
+
+	var diffFormat string
+	var pushChanged bool
+	diffCmd := &cobra.Command{
+		Use:   "diff OLD_LEADERBOARD NEW_LEADERBOARD",
+		Short: "Reports the differences between two leaderboards",
+		Long: `Reports the differences between two leaderboards
+
+Both leaderboards are files as output by the other leaderboard commands. Entrants are matched by their
+"address". The diff reports the entrants who were added to or removed from the leaderboard, and, for the
+entrants whose standing changed, their old and new ranks and scores and the components of their
+"points_data" which changed. Entrants are compared by their "rank" or, for leaderboards output by older
+versions of this tool, by score (with entrants with equal scores sharing a rank).
+
+The diff is output as JSON by default, or as a table with --format table.
+
+With --push-changed, only the added and changed entries of the new leaderboard are pushed to the sink
+(see --push), without overwriting the rest of the leaderboard. Entrants who were removed are not removed
+from the sink.
+`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if diffFormat != "json" && diffFormat != "table" {
+				return fmt.Errorf("invalid format (expected \"json\" or \"table\"): %s", diffFormat)
+			}
+			if pushChanged && !push {
+				return errors.New("--push-changed requires --push")
+			}
+
+			oldLeaderboard, oldErr := ReadLeaderboardFile(args[0])
+			if oldErr != nil {
+				return oldErr
+			}
+			newLeaderboard, newErr := ReadLeaderboardFile(args[1])
+			if newErr != nil {
+				return newErr
+			}
+
+			diff, diffErr := DiffLeaderboards(oldLeaderboard, newLeaderboard)
+			if diffErr != nil {
+				return diffErr
 			}
 
 			ofp := os.Stdout
@@ -421,35 +1066,73 @@ the adventurer in a game session.
 				defer ofp.Close()
 			}
 
-			leaderboard, leaderboardErr := LootSurvivorLeaderboard(ifp)
-			if leaderboardErr != nil {
-				return leaderboardErr
+			if diffFormat == "table" {
+				tableErr := diff.WriteTable(ofp)
+				if tableErr != nil {
+					return tableErr
+				}
+			} else {
+				outputEncoder := json.NewEncoder(ofp)
+				outputEncoder.Encode(diff)
 			}
 
-			outputEncoder := json.NewEncoder(ofp)
-			outputEncoder.Encode(leaderboard)
+			if pushChanged {
+				sink, sinkErr := NewLeaderboardSink(sinkKind, sinkTarget, accessToken, pushConfig)
+				if sinkErr != nil {
+					return sinkErr
+				}
+				defer func() {
+					closeErr := sink.Close()
+					if err == nil {
+						err = closeErr
+					}
+				}()
 
-			if push {
-				pushErr := Push(leaderboardID, accessToken, leaderboard, true)
-				if pushErr != nil {
-					return pushErr
+				changed := diff.ChangedEntries()
+				if len(changed) == 0 {
+					fmt.Fprintln(os.Stderr, "No entries changed, so nothing was pushed")
+					return nil
+				}
+				if diff.Removed > 0 {
+					fmt.Fprintf(os.Stderr, "Warning: %d entrants were removed from the leaderboard, but remain on the sink\n", diff.Removed)
 				}
+				return sink.Push(leaderboardID, changed, false)
 			}
 			return nil
 		},
 	}
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "json", "Format in which to output the diff: \"json\" or \"table\"")
+	diffCmd.Flags().BoolVar(&pushChanged, "push-changed", false, "Push only the added and changed entries of the new leaderboard to the sink, without overwriting the leaderboard (requires --push)")
 
-	leaderboardsCmd.AddCommand(totalCmd)
+	leaderboardsCmd.AddCommand(totalCmd, beastSlayersCmd, artfulDodgersCmd, diffCmd)
 
 	return leaderboardsCmd
 }
 
 func CreateParseCommand() *cobra.Command {
 	var infile, outfile string
+	var names, derived, decodeEnums bool
 
 	parseCmd := &cobra.Command{
 		Use:   "parse",
 		Short: "Parse a file (as produced by the \"stark events\" command) to process previously unknown events",
+		Long: `Parse a file (as produced by the "stark events" command) to process previously unknown events
+
+With --names, each parsed event is written with a Names object, which maps the path of every field that
+holds the ID of an item, beast or obstacle, or an item or beast special, to its name (e.g.
+"AdventurerState.Adventurer.Weapon.Id": "Katana"). Adventurer names, which the contract stores as Cairo
+short strings, are decoded in the same way (e.g. "AdventurerMeta.Name": "loaf"), with any characters that
+are not printable ASCII escaped as \xNN.
+
+With --derived, each parsed event is written with a Derived object, which holds the values that the game
+derives from XP: the level of each adventurer, and the greatness of each item along with whether it has
+unlocked its suffix and name prefixes (e.g. "AdventurerState.Adventurer.Level": 4). The same paths can be
+used as the fields of multipliers in a leaderboard scoring file.
+
+With --decode-enums, the Tier, ItemType, Slot, CriticalHit and Mutated fields of parsed events are written
+as objects holding both the raw value and its name (e.g. "Slot": {"Value": 1, "Name": "Weapon"}). Files
+written with --decode-enums are meant for reading, and cannot be used as input to the other commands.
+`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ifp := os.Stdin
 			var infileErr error
@@ -471,7 +1154,7 @@ func CreateParseCommand() *cobra.Command {
 				defer ofp.Close()
 			}
 
-			parser, newParserErr := NewEventParser()
+			parser, newParserErr := NewCrawledEventParser()
 			if newParserErr != nil {
 				return newParserErr
 			}
@@ -480,58 +1163,535 @@ func CreateParseCommand() *cobra.Command {
 
 			scanner := bufio.NewScanner(ifp)
 			for scanner.Scan() {
-				var partialEvent PartialEvent
-				line := scanner.Text()
-				json.Unmarshal([]byte(line), &partialEvent)
+				var partialEvent PartialCrawledEvent
+				line := scanner.Bytes()
+				json.Unmarshal(line, &partialEvent)
 
-				passThrough := true
+				outputBytes := line
 
 				if partialEvent.Name == EVENT_UNKNOWN {
 					var event RawEvent
 					json.Unmarshal(partialEvent.Event, &event)
-					parsedEvent, parseErr := parser.Parse(event)
-					if parseErr == nil {
-						passThrough = false
+					crawledEvent := ParseCrawledEvent(parser, event, partialEvent.EventIndex)
 
-						parsedEventBytes, marshalErr := json.Marshal(parsedEvent)
-						if marshalErr != nil {
-							return marshalErr
-						}
+					var marshalErr error
+					outputBytes, marshalErr = json.Marshal(crawledEvent)
+					if marshalErr != nil {
+						return marshalErr
+					}
+				}
 
-						_, writeErr := ofp.Write(parsedEventBytes)
-						if writeErr != nil {
-							return writeErr
+				if names || derived || decodeEnums {
+					json.Unmarshal(outputBytes, &partialEvent)
+					if partialEvent.Name != EVENT_UNKNOWN && partialEvent.Name != EVENT_RETRACTED {
+						if names {
+							var namesErr error
+							partialEvent.Names, namesErr = EventNames(partialEvent.Event)
+							if namesErr != nil {
+								return namesErr
+							}
 						}
-						_, writeErr = ofp.Write(newline)
-						if writeErr != nil {
-							return writeErr
+						if derived {
+							var derivedErr error
+							partialEvent.Derived, derivedErr = EventDerivedFields(partialEvent.Event)
+							if derivedErr != nil {
+								return derivedErr
+							}
+						}
+						if decodeEnums {
+							var decodeErr error
+							partialEvent.Event, decodeErr = DecodeEnums(partialEvent.Event)
+							if decodeErr != nil {
+								return decodeErr
+							}
+						}
+
+						var marshalErr error
+						outputBytes, marshalErr = json.Marshal(partialEvent)
+						if marshalErr != nil {
+							return marshalErr
 						}
 					}
 				}
 
-				if passThrough {
-					partialEventBytes, marshalErr := json.Marshal(partialEvent)
-					if marshalErr != nil {
-						return marshalErr
-					}
+				_, writeErr := ofp.Write(outputBytes)
+				if writeErr != nil {
+					return writeErr
+				}
+				_, writeErr = ofp.Write(newline)
+				if writeErr != nil {
+					return writeErr
+				}
+			}
+
+			return scanner.Err()
+		},
+	}
+
+	parseCmd.Flags().StringVarP(&infile, "infile", "i", "", "File containing crawled events from which to build the leaderboard (as produced by the \"loot-survivor stark events\" command, defaults to stdin)")
+	parseCmd.Flags().StringVarP(&outfile, "outfile", "o", "", "File to write reparsed events to (defaults to stdout)")
+	parseCmd.Flags().BoolVar(&names, "names", false, "Set this option to add the names of the items, beasts, obstacles and specials that each event refers to, and the decoded names of adventurers")
+	parseCmd.Flags().BoolVar(&derived, "derived", false, "Set this option to add the adventurer levels and item greatness (with unlocked suffixes and prefixes) derived from the XP in each event")
+	parseCmd.Flags().BoolVar(&decodeEnums, "decode-enums", false, "Set this option to write enum fields (Tier, ItemType, Slot, CriticalHit, Mutated) as both their raw value and their name")
+
+	return parseCmd
+}
+
+func CreateStoreCommand() *cobra.Command {
+	var storePath string
+
+	storeCmd := &cobra.Command{
+		Use:   "store",
+		Short: "Manage a local store of crawled events",
+		Long: `Manage a local store of crawled events
+
+The event store is a SQLite database which "stark events --store" writes events to, and which the
+leaderboards commands can read events from (with --store). Events in the store are indexed by event name,
+adventurer ID, adventurer owner and block number, and are deduplicated by transaction hash, emitting
+contract and event index.
+`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if storePath == "" {
+				return errors.New("you must specify the event store using -s/--store")
+			}
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	storeCmd.PersistentFlags().StringVarP(&storePath, "store", "s", "", "Path to the event store (SQLite database)")
+
+	var infile string
+	var batchSize int
+
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import a file of crawled events (as produced by the \"stark events\" command) into the event store",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ifp := os.Stdin
+			var infileErr error
+			if infile != "" && infile != "-" {
+				ifp, infileErr = os.Open(infile)
+				if infileErr != nil {
+					return infileErr
+				}
+				defer ifp.Close()
+			}
+
+			store, storeErr := OpenEventStore(storePath)
+			if storeErr != nil {
+				return storeErr
+			}
+			defer store.Close()
 
-					_, writeErr := ofp.Write(partialEventBytes)
-					if writeErr != nil {
-						return writeErr
+			inserted, importErr := store.Import(ifp, batchSize)
+			if importErr != nil {
+				return importErr
+			}
+
+			fmt.Fprintf(os.Stderr, "Net number of events added to the store: %d\n", inserted)
+			return nil
+		},
+	}
+
+	importCmd.Flags().StringVarP(&infile, "infile", "i", "", "File containing crawled events (defaults to stdin)")
+	importCmd.Flags().IntVarP(&batchSize, "batch-size", "N", 1000, "Number of events to write to the store per transaction")
+
+	var outfile, adventurerID, owner string
+	var names []string
+	var fromBlock, toBlock uint64
+
+	queryCmd := &cobra.Command{
+		Use:   "query",
+		Short: "Write the events in the event store which match the given filters, in the order they were emitted",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ofp := os.Stdout
+			var outfileErr error
+			if outfile != "" {
+				ofp, outfileErr = os.Create(outfile)
+				if outfileErr != nil {
+					return outfileErr
+				}
+				defer ofp.Close()
+			}
+
+			store, storeErr := OpenEventStore(storePath)
+			if storeErr != nil {
+				return storeErr
+			}
+			defer store.Close()
+
+			query := EventQuery{
+				Names:        names,
+				AdventurerID: adventurerID,
+				Owner:        owner,
+				FromBlock:    fromBlock,
+				ToBlock:      toBlock,
+			}
+			return store.Export(query, ofp)
+		},
+	}
+
+	queryCmd.Flags().StringVarP(&outfile, "outfile", "o", "", "File to write events to (defaults to stdout)")
+	queryCmd.Flags().StringSliceVarP(&names, "name", "n", nil, "Only return events with this name (e.g. game::Game::SlayedBeast); may be specified multiple times")
+	queryCmd.Flags().StringVarP(&adventurerID, "adventurer", "a", "", "Only return events concerning the adventurer with this ID")
+	queryCmd.Flags().StringVar(&owner, "owner", "", "Only return events concerning adventurers owned by this address")
+	queryCmd.Flags().Uint64Var(&fromBlock, "from", 0, "Only return events from this block onwards")
+	queryCmd.Flags().Uint64Var(&toBlock, "to", 0, "Only return events up to and including this block (set to 0 for no limit)")
+
+	storeCmd.AddCommand(importCmd, queryCmd)
+
+	return storeCmd
+}
+
+func CreateAdventurerCommand() *cobra.Command {
+	adventurerCmd := &cobra.Command{
+		Use:   "adventurer",
+		Short: "Inspect individual adventurers",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	var infile, outfile, storePath, format string
+	var reorgDepth uint64
+
+	historyCmd := &cobra.Command{
+		Use:   "history ADVENTURER_ID",
+		Short: "Timeline of the events concerning an adventurer",
+		Long: `Timeline of the events concerning an adventurer
+
+Reads parsed events (as produced by the "stark events" command) from a file or from the event store, and
+outputs every event which concerns the given adventurer (in decimal, or as a 0x-prefixed hex string), in
+the order in which the events were emitted: the start of their game, each discovery, battle, flight,
+purchase, level up and change of equipment, and finally their death. Each step reports the adventurer's
+health, gold and XP as carried in the event.
+
+The timeline is output as a table by default, or as JSON with --format json.
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "json" && format != "table" {
+				return fmt.Errorf("invalid format (expected \"json\" or \"table\"): %s", format)
+			}
+			adventurerID, idErr := NormalizeAdventurerID(args[0])
+			if idErr != nil {
+				return idErr
+			}
+
+			var events io.Reader
+			if storePath != "" {
+				store, storeErr := OpenEventStore(storePath)
+				if storeErr != nil {
+					return storeErr
+				}
+				defer store.Close()
+
+				// The store has already removed any retracted events.
+				events = store.Reader(EventQuery{AdventurerID: adventurerID})
+			} else {
+				ifp := os.Stdin
+				var infileErr error
+				if infile != "" && infile != "-" {
+					ifp, infileErr = os.Open(infile)
+					if infileErr != nil {
+						return infileErr
 					}
-					_, writeErr = ofp.Write(newline)
-					if writeErr != nil {
-						return writeErr
+					defer ifp.Close()
+				}
+				events = WithoutRetractedEvents(ifp, reorgDepth)
+			}
+
+			timeline, historyErr := AdventurerHistory(events, adventurerID)
+			if historyErr != nil {
+				return historyErr
+			}
+
+			ofp := os.Stdout
+			var outfileErr error
+			if outfile != "" {
+				ofp, outfileErr = os.Create(outfile)
+				if outfileErr != nil {
+					return outfileErr
+				}
+				defer ofp.Close()
+			}
+
+			if format == "table" {
+				return WriteTimelineTable(ofp, timeline)
+			}
+			outputEncoder := json.NewEncoder(ofp)
+			return outputEncoder.Encode(timeline)
+		},
+	}
+
+	historyCmd.Flags().StringVarP(&infile, "infile", "i", "", "File containing crawled events from which to build the timeline (defaults to stdin)")
+	historyCmd.Flags().StringVarP(&storePath, "store", "s", "", "Event store (as written by \"stark events --store\") from which to build the timeline, instead of --infile")
+	historyCmd.Flags().Uint64Var(&reorgDepth, "reorg-depth", 64, "The --reorg-depth with which the events were crawled (retracted events must refer to one of this many preceding blocks)")
+	historyCmd.Flags().StringVarP(&outfile, "outfile", "o", "", "File to write the timeline to (defaults to stdout)")
+	historyCmd.Flags().StringVarP(&format, "format", "f", "table", "Format in which to output the timeline: \"table\" or \"json\"")
+
+	var providerURLs []string
+	var contractAddress string
+	var timeout, blockNumber uint64
+	var retries int
+
+	stateCmd := &cobra.Command{
+		Use:   "state ADVENTURER_ID",
+		Short: "Live state of an adventurer, read from the LootSurvivor contract",
+		Long: `Live state of an adventurer, read from the LootSurvivor contract
+
+Calls the get_adventurer, get_adventurer_meta, get_bag, get_stats and get_items_on_market view functions
+of the LootSurvivor contract for the given adventurer (in decimal, or as a 0x-prefixed hex string), and
+outputs the adventurer's state as JSON. All the views are called at the same block, which is reported in
+the output: the current head of the chain or, with --block, an earlier block.
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if contractAddress == "" {
+				return errors.New("you must provide the address of the LootSurvivor contract using -c/--contract")
+			}
+			contractFelt, contractErr := new(felt.Felt).SetString(contractAddress)
+			if contractErr != nil {
+				return fmt.Errorf("invalid contract address %s: %w", contractAddress, contractErr)
+			}
+
+			urls := ProviderURLs(providerURLs)
+			if len(urls) == 0 {
+				return errors.New("you must provide a provider URL using -p/--provider or set the STARKNET_RPC_URL environment variable")
+			}
+			providers, poolErr := NewProviderPool(urls, RetryConfig{MaxRetries: retries, InitialBackoff: 500 * time.Millisecond, MaxBackoff: 30 * time.Second})
+			if poolErr != nil {
+				return poolErr
+			}
+
+			ctx := context.Background()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithDeadline(ctx, time.Now().Add(time.Duration(timeout)*time.Second))
+				defer cancel()
+			}
+
+			snapshot, snapshotErr := SnapshotAdventurer(ctx, providers, contractFelt, args[0], blockNumber)
+			if snapshotErr != nil {
+				return snapshotErr
+			}
+
+			ofp := os.Stdout
+			var outfileErr error
+			if outfile != "" {
+				ofp, outfileErr = os.Create(outfile)
+				if outfileErr != nil {
+					return outfileErr
+				}
+				defer ofp.Close()
+			}
+
+			outputEncoder := json.NewEncoder(ofp)
+			return outputEncoder.Encode(snapshot)
+		},
+	}
+
+	stateCmd.Flags().StringSliceVarP(&providerURLs, "provider", "p", nil, "The URL of your Starknet RPC provider (defaults to value of STARKNET_RPC_URL environment variable); specify multiple times, or as a comma-separated list, to fail over between several providers")
+	stateCmd.Flags().StringVarP(&contractAddress, "contract", "c", "", "The address of the LootSurvivor contract")
+	stateCmd.Flags().Uint64VarP(&timeout, "timeout", "t", 0, "The timeout (in seconds) for the calls to your Starknet RPC provider")
+	stateCmd.Flags().Uint64Var(&blockNumber, "block", 0, "Block at which to read the adventurer's state (defaults to the current head of the chain)")
+	stateCmd.Flags().IntVar(&retries, "retries", 5, "Number of times to retry a request to your Starknet RPC provider(s) which fails with a network error, a 429 response, or a 5xx response before giving up")
+	stateCmd.Flags().StringVarP(&outfile, "outfile", "o", "", "File to write the adventurer's state to (defaults to stdout)")
+
+	boostsCmd := &cobra.Command{
+		Use:   "boosts ADVENTURER_ID",
+		Short: "Check the stat boosts of an adventurer's items against the LootSurvivor contract",
+		Long: `Check the stat boosts of an adventurer's items against the LootSurvivor contract
+
+Reads the adventurer's unboosted state (get_adventurer_no_boosts), the specials of their equipped items
+(get_weapon_specials, get_chest_specials, ...) and their boosted state (get_adventurer) from the
+LootSurvivor contract, all at the same block. Computes the adventurer's effective stats from the boosts
+granted by the suffixes of the items which have reached greatness 15, and compares them to the stats that
+the contract reports.
+
+Outputs the base stats, the specials of the items, the boosts, and the computed and contract stats as
+JSON, and exits with an error if the computed stats do not match the contract's. Use this to check the
+suffix boost table against a deployed contract (e.g. for adventurers whose items have unlocked their
+suffixes) before relying on computed stats elsewhere.
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if contractAddress == "" {
+				return errors.New("you must provide the address of the LootSurvivor contract using -c/--contract")
+			}
+			contractFelt, contractErr := new(felt.Felt).SetString(contractAddress)
+			if contractErr != nil {
+				return fmt.Errorf("invalid contract address %s: %w", contractAddress, contractErr)
+			}
+
+			urls := ProviderURLs(providerURLs)
+			if len(urls) == 0 {
+				return errors.New("you must provide a provider URL using -p/--provider or set the STARKNET_RPC_URL environment variable")
+			}
+			providers, poolErr := NewProviderPool(urls, RetryConfig{MaxRetries: retries, InitialBackoff: 500 * time.Millisecond, MaxBackoff: 30 * time.Second})
+			if poolErr != nil {
+				return poolErr
+			}
+
+			ctx := context.Background()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithDeadline(ctx, time.Now().Add(time.Duration(timeout)*time.Second))
+				defer cancel()
+			}
+
+			check, checkErr := CheckStatBoosts(ctx, providers, contractFelt, args[0], blockNumber)
+			if checkErr != nil {
+				return checkErr
+			}
+
+			ofp := os.Stdout
+			var outfileErr error
+			if outfile != "" {
+				ofp, outfileErr = os.Create(outfile)
+				if outfileErr != nil {
+					return outfileErr
+				}
+				defer ofp.Close()
+			}
+
+			outputEncoder := json.NewEncoder(ofp)
+			encodeErr := outputEncoder.Encode(check)
+			if encodeErr != nil {
+				return encodeErr
+			}
+
+			if !check.Match {
+				return fmt.Errorf("computed stats of adventurer %s do not match the contract's stats at block %d", check.AdventurerId, check.BlockNumber)
+			}
+			return nil
+		},
+	}
+
+	boostsCmd.Flags().StringSliceVarP(&providerURLs, "provider", "p", nil, "The URL of your Starknet RPC provider (defaults to value of STARKNET_RPC_URL environment variable); specify multiple times, or as a comma-separated list, to fail over between several providers")
+	boostsCmd.Flags().StringVarP(&contractAddress, "contract", "c", "", "The address of the LootSurvivor contract")
+	boostsCmd.Flags().Uint64VarP(&timeout, "timeout", "t", 0, "The timeout (in seconds) for the calls to your Starknet RPC provider")
+	boostsCmd.Flags().Uint64Var(&blockNumber, "block", 0, "Block at which to read the adventurer's state (defaults to the current head of the chain)")
+	boostsCmd.Flags().IntVar(&retries, "retries", 5, "Number of times to retry a request to your Starknet RPC provider(s) which fails with a network error, a 429 response, or a 5xx response before giving up")
+	boostsCmd.Flags().StringVarP(&outfile, "outfile", "o", "", "File to write the check to (defaults to stdout)")
+
+	adventurerCmd.AddCommand(historyCmd, stateCmd, boostsCmd)
+
+	return adventurerCmd
+}
+
+func CreateBeastsCommand() *cobra.Command {
+	beastsCmd := &cobra.Command{
+		Use:   "beasts",
+		Short: "Inspect the Beasts NFT contract",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	var infile, outfile, storePath string
+	var reorgDepth uint64
+
+	holdersCmd := &cobra.Command{
+		Use:   "holders",
+		Short: "Current owner of each beast token, rebuilt from Transfer events",
+		Long: `Current owner of each beast token, rebuilt from Transfer events
+
+Reads parsed events of the Beasts NFT contract (as produced by "stark events --parse" for the Beasts
+contract) from a file or from the event store, and replays the Transfer events in the order in which they
+were emitted. Outputs a JSON array with the owner of each token that has not been burned, along with the
+block and transaction in which the token was transferred to its owner, in order of token ID.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var events io.Reader
+			if storePath != "" {
+				store, storeErr := OpenEventStore(storePath)
+				if storeErr != nil {
+					return storeErr
+				}
+				defer store.Close()
+
+				// The store has already removed any retracted events.
+				events = store.Reader(EventQuery{Names: []string{Event_LootSurvivorBeasts_Beasts_Beasts_Transfer}})
+			} else {
+				ifp := os.Stdin
+				var infileErr error
+				if infile != "" && infile != "-" {
+					ifp, infileErr = os.Open(infile)
+					if infileErr != nil {
+						return infileErr
 					}
+					defer ifp.Close()
 				}
+				events = WithoutRetractedEvents(ifp, reorgDepth)
 			}
 
+			holders, holdersErr := BeastHolders(events)
+			if holdersErr != nil {
+				return holdersErr
+			}
+
+			ofp := os.Stdout
+			var outfileErr error
+			if outfile != "" {
+				ofp, outfileErr = os.Create(outfile)
+				if outfileErr != nil {
+					return outfileErr
+				}
+				defer ofp.Close()
+			}
+
+			outputEncoder := json.NewEncoder(ofp)
+			return outputEncoder.Encode(holders)
+		},
+	}
+
+	holdersCmd.Flags().StringVarP(&infile, "infile", "i", "", "File containing crawled events of the Beasts contract (defaults to stdin)")
+	holdersCmd.Flags().StringVarP(&storePath, "store", "s", "", "Event store (as written by \"stark events --store\") from which to read the events, instead of --infile")
+	holdersCmd.Flags().Uint64Var(&reorgDepth, "reorg-depth", 64, "The --reorg-depth with which the events were crawled (retracted events must refer to one of this many preceding blocks)")
+	holdersCmd.Flags().StringVarP(&outfile, "outfile", "o", "", "File to write the holders to (defaults to stdout)")
+
+	beastsCmd.AddCommand(holdersCmd)
+
+	return beastsCmd
+}
+
+func CreateFeltCommand() *cobra.Command {
+	var asShortString bool
+
+	feltCmd := &cobra.Command{
+		Use:   "felt VALUE [VALUE...]",
+		Short: "Convert felts between hex, decimal and Cairo short strings",
+		Long: `Convert felts between hex, decimal and Cairo short strings
+
+Reads each value as a 0x-prefixed hex number or a decimal number or, with -s/--short-string, as a Cairo
+short string (at most 31 ASCII characters, such as the name of an adventurer). Writes one JSON object per
+value, holding the felt in hex, in decimal and, if it is small enough to encode one, as a short string.
+
+Characters of short strings that are not printable ASCII are written as \xNN, and backslashes as \\. The
+same escapes can be used in the values given with -s/--short-string.
+`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outputEncoder := json.NewEncoder(cmd.OutOrStdout())
+			for _, arg := range args {
+				conversion, convertErr := ConvertFelt(arg, asShortString)
+				if convertErr != nil {
+					return fmt.Errorf("could not convert %s: %w", arg, convertErr)
+				}
+				encodeErr := outputEncoder.Encode(conversion)
+				if encodeErr != nil {
+					return encodeErr
+				}
+			}
 			return nil
 		},
 	}
 
-	parseCmd.Flags().StringVarP(&infile, "infile", "i", "", "File containing crawled events from which to build the leaderboard (as produced by the \"loot-survivor stark events\" command, defaults to stdin)")
-	parseCmd.Flags().StringVarP(&outfile, "outfile", "o", "", "File to write reparsed events to (defaults to stdout)")
+	feltCmd.Flags().BoolVarP(&asShortString, "short-string", "s", false, "Set this option to read the values as Cairo short strings instead of numbers")
 
-	return parseCmd
+	return feltCmd
 }
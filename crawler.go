@@ -0,0 +1,628 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+var ErrPendingBlock error = errors.New("block is pending")
+var ErrReorgTooDeep error = errors.New("chain reorganization is deeper than the number of blocks the crawler remembers")
+var ErrPartialRangeWithMultipleContracts error = errors.New("cannot resume a crawl of several contracts from the middle of a block range (the checkpoint was written by a crawl of a single contract)")
+
+// Code of the Starknet JSON-RPC error which a provider returns for a block that it does not have.
+var STARKNET_ERROR_BLOCK_NOT_FOUND int = 24
+
+// Returns true if err is a provider's response that the requested block does not exist.
+func IsBlockNotFound(err error) bool {
+	var rpcErr ethrpc.Error
+	return errors.As(err, &rpcErr) && rpcErr.ErrorCode() == STARKNET_ERROR_BLOCK_NOT_FOUND
+}
+
+// IndexedEvent is a crawled event together with its index among the events that the same contract emitted
+// in its transaction. Together with the transaction hash and the address of the contract, the index
+// identifies the event. Since it only counts the events of one contract, the index does not depend on
+// which other contracts are crawled, so crawls of different sets of contracts index an event the same way.
+type IndexedEvent struct {
+	Event RawEvent
+	Index uint64
+}
+
+// EventID identifies an event that the crawler has emitted.
+type EventID struct {
+	TransactionHash *felt.Felt `json:"transaction_hash"`
+	FromAddress     *felt.Felt `json:"from_address"`
+	EventIndex      uint64     `json:"event_index"`
+}
+
+// RetractedEvent identifies an event which the crawler emitted from a block that has since been replaced
+// in a chain reorganization.
+type RetractedEvent struct {
+	BlockNumber uint64
+	BlockHash   *felt.Felt
+	EventID
+}
+
+// RecentBlock is a block that the crawler has crawled recently, along with the events that it emitted
+// from that block. The crawler remembers recent blocks so that it can detect when they are replaced.
+type RecentBlock struct {
+	Number uint64     `json:"number"`
+	Hash   *felt.Felt `json:"hash"`
+	Events []EventID  `json:"events,omitempty"`
+}
+
+// CrawlCursor marks the position of a crawl. If ContinuationToken is non-empty, the crawl is in the middle
+// of the page of events for [FromBlock, ToBlock] that the token refers to. Otherwise, the next request
+// will be for the events starting at FromBlock. A ToBlock of 0 means that the upper bound of the next
+// request will be determined from the current block number.
+//
+// The cursor also holds the state the crawler needs to index events within their transactions and to
+// detect chain reorganizations, so that this state survives when a crawl is resumed from a checkpoint.
+type CrawlCursor struct {
+	FromBlock         uint64 `json:"from_block"`
+	ToBlock           uint64 `json:"to_block"`
+	ContinuationToken string `json:"continuation_token"`
+	// The events emitted by the crawl from the transaction of its last event.
+	LastTransaction []EventID `json:"last_transaction,omitempty"`
+	// Blocks crawled within the last ReorgDepth blocks, in ascending order.
+	RecentBlocks []RecentBlock `json:"recent_blocks,omitempty"`
+}
+
+// CrawlBatch is a chunk of events as returned by the RPC provider, together with the cursor from which the
+// crawl resumes once those events have been handled. If the crawler detected a chain reorganization
+// before fetching the chunk, Retracted lists the events it had emitted from the replaced blocks. These
+// must be handled before Events.
+type CrawlBatch struct {
+	Retracted []RetractedEvent
+	Events    []IndexedEvent
+	Next      CrawlCursor
+}
+
+// CrawlerConfig holds the parameters of a crawl which do not change as the crawl progresses.
+type CrawlerConfig struct {
+	// Addresses of the contracts whose events should be crawled. If empty, events are crawled for all
+	// contracts.
+	ContractAddresses []string
+	// When crawling several contracts, the events of every contract in a block range must be fetched
+	// before they can be interleaved (see InterleaveEvents), so the crawler fetches at most RangeSize
+	// blocks at a time. If 0, there is no limit.
+	RangeSize uint64
+	// Number of successive iterations which must return events before the crawl is considered hot.
+	HotThreshold int
+	// Polling intervals for hot and cold crawls.
+	HotInterval  time.Duration
+	ColdInterval time.Duration
+	// Block at which to stop crawling. If 0, the crawl is continuous.
+	ToBlock uint64
+	// Number of confirmations a block needs before it is crawled.
+	Confirmations int
+	// Number of events to request from the provider at a time.
+	BatchSize int
+	// Number of blocks behind the most recently crawled block for which the crawler remembers block
+	// hashes in order to detect chain reorganizations. If 0, the crawler does not check for
+	// reorganizations.
+	ReorgDepth uint64
+}
+
+// Returns the hash of the block with the given number.
+func BlockHash(ctx context.Context, provider *rpc.Provider, blockNumber uint64) (*felt.Felt, error) {
+	block, blockErr := provider.BlockWithTxHashes(ctx, rpc.BlockID{Number: &blockNumber})
+	if blockErr != nil {
+		return nil, blockErr
+	}
+
+	acceptedBlock, ok := block.(*rpc.BlockTxHashes)
+	if !ok {
+		return nil, ErrPendingBlock
+	}
+
+	return acceptedBlock.BlockHash, nil
+}
+
+// Checks whether any of the cursor's recent blocks have been replaced on the chain. If they have, removes
+// them from the cursor, rewinds the cursor to the first replaced block, and returns the events which were
+// emitted from the replaced blocks.
+//
+// Blocks are checked from the most recent one backwards. A block can only be replaced if all the blocks
+// after it are replaced as well, so if there has been no reorganization this takes a single request. A
+// block which no longer exists (because the reorganization shortened the chain) counts as replaced.
+func CheckForReorg(ctx context.Context, providers *ProviderPool, cursor *CrawlCursor) ([]RetractedEvent, error) {
+	if len(cursor.RecentBlocks) == 0 {
+		return nil, nil
+	}
+
+	// Index of the most recent block which is still on the chain.
+	canonical := len(cursor.RecentBlocks) - 1
+	for ; canonical >= 0; canonical-- {
+		recentBlock := cursor.RecentBlocks[canonical]
+		var currentHash *felt.Felt
+		hashErr := providers.Do(ctx, func(provider *rpc.Provider) error {
+			var err error
+			currentHash, err = BlockHash(ctx, provider, recentBlock.Number)
+			return err
+		})
+		if IsBlockNotFound(hashErr) {
+			continue
+		}
+		if hashErr != nil {
+			return nil, hashErr
+		}
+		if currentHash.Equal(recentBlock.Hash) {
+			break
+		}
+	}
+
+	if canonical == len(cursor.RecentBlocks)-1 {
+		return nil, nil
+	}
+	if canonical < 0 {
+		return nil, fmt.Errorf("%w: block %d has been replaced", ErrReorgTooDeep, cursor.RecentBlocks[0].Number)
+	}
+
+	var retracted []RetractedEvent
+	for _, replacedBlock := range cursor.RecentBlocks[canonical+1:] {
+		for _, eventID := range replacedBlock.Events {
+			retracted = append(retracted, RetractedEvent{BlockNumber: replacedBlock.Number, BlockHash: replacedBlock.Hash, EventID: eventID})
+		}
+	}
+
+	cursor.FromBlock = cursor.RecentBlocks[canonical].Number + 1
+	cursor.ContinuationToken = ""
+	cursor.LastTransaction = nil
+	cursor.RecentBlocks = cursor.RecentBlocks[:canonical+1]
+
+	return retracted, nil
+}
+
+// Returns a deep copy of the cursor, which can be handed to another goroutine while the crawler continues
+// to update the original.
+func (cursor CrawlCursor) Copy() CrawlCursor {
+	result := cursor
+	if cursor.LastTransaction != nil {
+		result.LastTransaction = append([]EventID(nil), cursor.LastTransaction...)
+	}
+	if cursor.RecentBlocks != nil {
+		result.RecentBlocks = make([]RecentBlock, len(cursor.RecentBlocks))
+		for i, recentBlock := range cursor.RecentBlocks {
+			result.RecentBlocks[i] = recentBlock
+			result.RecentBlocks[i].Events = append([]EventID(nil), recentBlock.Events...)
+		}
+	}
+	return result
+}
+
+// Records a crawled event in the cursor, and returns its index among the events that its contract emitted
+// in its transaction. The events of a transaction are always crawled one after the other.
+func (cursor *CrawlCursor) recordEvent(event RawEvent, reorgDepth uint64) uint64 {
+	if len(cursor.LastTransaction) > 0 && !cursor.LastTransaction[0].TransactionHash.Equal(event.TransactionHash) {
+		cursor.LastTransaction = nil
+	}
+	var eventIndex uint64
+	for _, previous := range cursor.LastTransaction {
+		if previous.FromAddress.Equal(event.FromAddress) {
+			eventIndex++
+		}
+	}
+	eventID := EventID{TransactionHash: event.TransactionHash, FromAddress: event.FromAddress, EventIndex: eventIndex}
+	cursor.LastTransaction = append(cursor.LastTransaction, eventID)
+
+	if reorgDepth > 0 {
+		// Blocks are also forgotten here, and not only at the end of each range, so that the cursor stays
+		// small over a crawl of a long range.
+		cursor.forgetBlocks(event.BlockNumber, reorgDepth)
+		numRecent := len(cursor.RecentBlocks)
+		if numRecent == 0 || cursor.RecentBlocks[numRecent-1].Number != event.BlockNumber {
+			cursor.RecentBlocks = append(cursor.RecentBlocks, RecentBlock{Number: event.BlockNumber, Hash: event.BlockHash})
+			numRecent++
+		}
+		cursor.RecentBlocks[numRecent-1].Events = append(cursor.RecentBlocks[numRecent-1].Events, eventID)
+	}
+
+	return eventIndex
+}
+
+// Records that the cursor has crawled every block up to and including the given block, whose hash is
+// blockHash, and forgets the blocks that are now more than reorgDepth blocks old.
+func (cursor *CrawlCursor) recordBlock(blockNumber uint64, blockHash *felt.Felt, reorgDepth uint64) {
+	numRecent := len(cursor.RecentBlocks)
+	if numRecent == 0 || cursor.RecentBlocks[numRecent-1].Number != blockNumber {
+		cursor.RecentBlocks = append(cursor.RecentBlocks, RecentBlock{Number: blockNumber, Hash: blockHash})
+	}
+
+	cursor.forgetBlocks(blockNumber, reorgDepth)
+}
+
+// Forgets the recent blocks which are more than reorgDepth blocks older than the given block.
+func (cursor *CrawlCursor) forgetBlocks(blockNumber uint64, reorgDepth uint64) {
+	oldest := 0
+	for oldest < len(cursor.RecentBlocks) && cursor.RecentBlocks[oldest].Number+reorgDepth <= blockNumber {
+		oldest++
+	}
+	cursor.RecentBlocks = cursor.RecentBlocks[oldest:]
+}
+
+// Converts the events returned by the RPC provider to RawEvents.
+func RawEvents(emittedEvents []rpc.EmittedEvent) []RawEvent {
+	events := make([]RawEvent, len(emittedEvents))
+	for i, event := range emittedEvents {
+		events[i] = RawEvent{
+			BlockNumber:     event.BlockNumber,
+			BlockHash:       event.BlockHash,
+			TransactionHash: event.TransactionHash,
+			FromAddress:     event.FromAddress,
+			Keys:            event.Keys,
+			Parameters:      event.Data,
+		}
+		if len(event.Keys) > 0 {
+			events[i].PrimaryKey = event.Keys[0]
+		}
+	}
+	return events
+}
+
+// Fetches all the events that a contract (or, if contractAddress is empty, any contract) emitted in a
+// block range, starting from the given continuation token and following continuation tokens until the
+// range is exhausted.
+func FetchContractEvents(ctx context.Context, providers *ProviderPool, contractAddress string, fromBlock, toBlock uint64, continuationToken string, batchSize int) ([]RawEvent, error) {
+	filter, filterErr := AllEventsFilter(fromBlock, toBlock, contractAddress)
+	if filterErr != nil {
+		return nil, filterErr
+	}
+
+	var events []RawEvent
+	for {
+		eventsInput := rpc.EventsInput{
+			EventFilter:       *filter,
+			ResultPageRequest: rpc.ResultPageRequest{ChunkSize: batchSize, ContinuationToken: continuationToken},
+		}
+
+		var eventsChunk *rpc.EventChunk
+		getEventsErr := providers.Do(ctx, func(provider *rpc.Provider) error {
+			var err error
+			eventsChunk, err = provider.Events(ctx, eventsInput)
+			return err
+		})
+		if getEventsErr != nil {
+			return nil, getEventsErr
+		}
+
+		events = append(events, RawEvents(eventsChunk.Events)...)
+
+		if eventsChunk.ContinuationToken == "" {
+			return events, nil
+		}
+		continuationToken = eventsChunk.ContinuationToken
+	}
+}
+
+// Fetches all the events that the crawled contracts emitted in a block range. If several contracts are
+// crawled, their events are interleaved (see InterleaveEvents).
+func FetchEvents(ctx context.Context, providers *ProviderPool, config CrawlerConfig, fromBlock, toBlock uint64) ([]RawEvent, error) {
+	if len(config.ContractAddresses) == 0 {
+		return FetchContractEvents(ctx, providers, "", fromBlock, toBlock, "", config.BatchSize)
+	}
+
+	eventsByContract := make([][]RawEvent, len(config.ContractAddresses))
+	for i, contractAddress := range config.ContractAddresses {
+		var fetchErr error
+		eventsByContract[i], fetchErr = FetchContractEvents(ctx, providers, contractAddress, fromBlock, toBlock, "", config.BatchSize)
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+	}
+	return InterleaveEvents(eventsByContract), nil
+}
+
+// Merges the events of several contracts, each in the order in which the RPC provider returned them,
+// into a single list in block order. Within a block, the events of each transaction are kept together,
+// and transactions are ordered by their first event, taking the contracts in the order given.
+//
+// Note: Starknet's event API does not report the position of a transaction in its block. If transactions
+// in the same block emitted events from different contracts, their order in the merged list may therefore
+// differ from the order in which they were executed. The order is the same every time the same events are
+// merged, so the events are always given the same indices.
+func InterleaveEvents(eventsByContract [][]RawEvent) []RawEvent {
+	if len(eventsByContract) == 1 {
+		return eventsByContract[0]
+	}
+
+	type transactionEvents struct {
+		events []RawEvent
+	}
+	blocks := make(map[uint64][]*transactionEvents)
+	transactions := make(map[string]*transactionEvents)
+	var blockNumbers []uint64
+	total := 0
+
+	for _, events := range eventsByContract {
+		for _, event := range events {
+			total++
+			var transactionKey string
+			if event.TransactionHash != nil {
+				transactionKey = event.TransactionHash.String()
+			}
+			transactionKey = fmt.Sprintf("%d:%s", event.BlockNumber, transactionKey)
+
+			transaction, ok := transactions[transactionKey]
+			if !ok {
+				transaction = &transactionEvents{}
+				transactions[transactionKey] = transaction
+				if _, seen := blocks[event.BlockNumber]; !seen {
+					blockNumbers = append(blockNumbers, event.BlockNumber)
+				}
+				blocks[event.BlockNumber] = append(blocks[event.BlockNumber], transaction)
+			}
+			transaction.events = append(transaction.events, event)
+		}
+	}
+
+	sort.Slice(blockNumbers, func(i, j int) bool { return blockNumbers[i] < blockNumbers[j] })
+
+	result := make([]RawEvent, 0, total)
+	for _, blockNumber := range blockNumbers {
+		for _, transaction := range blocks[blockNumber] {
+			result = append(result, transaction.events...)
+		}
+	}
+	return result
+}
+
+// Crawls events according to the given configuration, starting from the given cursor, and delivers them
+// on outChan one provider chunk at a time. Closes outChan when it returns. Requests to the RPC providers
+// are retried according to the pool's retry configuration, and an error is only returned once a request
+// has exhausted its retries.
+//
+// Unlike ContractEvents, this function hands the cursor following each chunk to the consumer along with
+// the chunk itself. This allows the consumer to persist a checkpoint once it has handled the events in a
+// chunk, so that a crawl can be resumed without gaps or duplicates.
+//
+// If several contracts are crawled, each batch holds all the events of those contracts from a range of up
+// to config.RangeSize blocks, interleaved in block order (see InterleaveEvents).
+//
+// If config.ReorgDepth is positive, then before crawling each new block range, the crawler checks that
+// the blocks it crawled recently are still on the chain. If some of them have been replaced, it retracts
+// the events it emitted from those blocks and crawls the replacement blocks.
+func CrawlContractEvents(ctx context.Context, providers *ProviderPool, config CrawlerConfig, cursor CrawlCursor, outChan chan<- CrawlBatch) error {
+	defer func() { close(outChan) }()
+
+	if len(config.ContractAddresses) > 1 && cursor.ContinuationToken != "" {
+		return ErrPartialRangeWithMultipleContracts
+	}
+
+	interval := config.HotInterval
+	heat := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+			var retracted []RetractedEvent
+			if config.ReorgDepth > 0 && cursor.ContinuationToken == "" {
+				var reorgErr error
+				retracted, reorgErr = CheckForReorg(ctx, providers, &cursor)
+				if reorgErr != nil {
+					return reorgErr
+				}
+			}
+
+			// If the crawl goes cold before it fetches any events (as it does when a reorganization shortens
+			// the chain), the retractions are delivered on their own so that they are not lost.
+			sendRetracted := func() bool {
+				if len(retracted) == 0 {
+					return true
+				}
+				select {
+				case <-ctx.Done():
+					return false
+				case outChan <- CrawlBatch{Retracted: retracted, Next: cursor.Copy()}:
+					return true
+				}
+			}
+
+			if cursor.ToBlock == 0 {
+				var currentBlock uint64
+				blockErr := providers.Do(ctx, func(provider *rpc.Provider) error {
+					var err error
+					currentBlock, err = provider.BlockNumber(ctx)
+					return err
+				})
+				if blockErr != nil {
+					return blockErr
+				}
+				if currentBlock < uint64(config.Confirmations) {
+					if !sendRetracted() {
+						return nil
+					}
+					interval = config.ColdInterval
+					break
+				}
+				cursor.ToBlock = currentBlock - uint64(config.Confirmations)
+				if config.ToBlock != 0 && cursor.ToBlock > config.ToBlock {
+					cursor.ToBlock = config.ToBlock
+				}
+			}
+
+			if cursor.ToBlock < cursor.FromBlock {
+				// Crawl is cold, slow things down.
+				interval = config.ColdInterval
+
+				if config.ToBlock == 0 {
+					// If the crawl is continuous, breaks out of select, not for loop.
+					// This effects a wait for the given interval.
+					cursor.ToBlock = 0
+					if !sendRetracted() {
+						return nil
+					}
+					break
+				} else {
+					// If crawl is not continuous, just ends the crawl.
+					sendRetracted()
+					return nil
+				}
+			}
+
+			var events []RawEvent
+			var continuationToken string
+			if len(config.ContractAddresses) > 1 {
+				// The events of each contract are fetched in full, so that they can be interleaved.
+				if config.RangeSize > 0 && cursor.ToBlock-cursor.FromBlock >= config.RangeSize {
+					cursor.ToBlock = cursor.FromBlock + config.RangeSize - 1
+				}
+				var fetchErr error
+				events, fetchErr = FetchEvents(ctx, providers, config, cursor.FromBlock, cursor.ToBlock)
+				if fetchErr != nil {
+					return fetchErr
+				}
+			} else {
+				var contractAddress string
+				if len(config.ContractAddresses) == 1 {
+					contractAddress = config.ContractAddresses[0]
+				}
+				filter, filterErr := AllEventsFilter(cursor.FromBlock, cursor.ToBlock, contractAddress)
+				if filterErr != nil {
+					return filterErr
+				}
+
+				eventsInput := rpc.EventsInput{
+					EventFilter:       *filter,
+					ResultPageRequest: rpc.ResultPageRequest{ChunkSize: config.BatchSize, ContinuationToken: cursor.ContinuationToken},
+				}
+
+				var eventsChunk *rpc.EventChunk
+				getEventsErr := providers.Do(ctx, func(provider *rpc.Provider) error {
+					var err error
+					eventsChunk, err = provider.Events(ctx, eventsInput)
+					return err
+				})
+				if getEventsErr != nil {
+					return getEventsErr
+				}
+
+				events = RawEvents(eventsChunk.Events)
+				continuationToken = eventsChunk.ContinuationToken
+			}
+
+			batch := CrawlBatch{Retracted: retracted, Events: make([]IndexedEvent, len(events))}
+			for i, event := range events {
+				batch.Events[i] = IndexedEvent{
+					Event: event,
+					Index: cursor.recordEvent(event, config.ReorgDepth),
+				}
+			}
+
+			if continuationToken != "" {
+				cursor.ContinuationToken = continuationToken
+				interval = config.HotInterval
+			} else {
+				if config.ReorgDepth > 0 {
+					var rangeEndHash *felt.Felt
+					hashErr := providers.Do(ctx, func(provider *rpc.Provider) error {
+						var err error
+						rangeEndHash, err = BlockHash(ctx, provider, cursor.ToBlock)
+						return err
+					})
+					if hashErr != nil {
+						return hashErr
+					}
+					cursor.recordBlock(cursor.ToBlock, rangeEndHash, config.ReorgDepth)
+				}
+
+				cursor.FromBlock = cursor.ToBlock + 1
+				cursor.ToBlock = config.ToBlock
+				cursor.ContinuationToken = ""
+				if len(events) > 0 {
+					heat++
+					if heat >= config.HotThreshold {
+						interval = config.HotInterval
+					}
+				} else {
+					heat = 0
+					interval = config.ColdInterval
+				}
+			}
+
+			batch.Next = cursor.Copy()
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case outChan <- batch:
+			}
+		}
+	}
+}
+
+// CrawlCheckpoint is the state that "stark events" persists in order to resume a crawl.
+type CrawlCheckpoint struct {
+	Cursor CrawlCursor `json:"cursor"`
+	// Size of the output file once all events up to Cursor had been written to it. Only set if the crawl
+	// writes to a file.
+	OutputOffset int64 `json:"output_offset,omitempty"`
+}
+
+// Loads a checkpoint from the given file. Returns a nil checkpoint (and no error) if the file does not
+// exist.
+func LoadCrawlCheckpoint(checkpointFile string) (*CrawlCheckpoint, error) {
+	contents, readErr := os.ReadFile(checkpointFile)
+	if readErr != nil {
+		if errors.Is(readErr, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, readErr
+	}
+
+	var checkpoint CrawlCheckpoint
+	unmarshalErr := json.Unmarshal(contents, &checkpoint)
+	if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+
+	return &checkpoint, nil
+}
+
+// Saves a checkpoint to the given file, atomically (see WriteFileAtomically).
+func SaveCrawlCheckpoint(checkpointFile string, checkpoint CrawlCheckpoint) error {
+	contents, marshalErr := json.Marshal(checkpoint)
+	if marshalErr != nil {
+		return marshalErr
+	}
+
+	return WriteFileAtomically(checkpointFile, contents)
+}
+
+// Writes contents to the given file. The contents are first written to a temporary file in the same
+// directory, which is then renamed over the file, so that the file is never left partially written.
+func WriteFileAtomically(filename string, contents []byte) error {
+	tmpfile, createErr := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp-*")
+	if createErr != nil {
+		return createErr
+	}
+	defer os.Remove(tmpfile.Name())
+
+	_, writeErr := tmpfile.Write(contents)
+	if writeErr != nil {
+		tmpfile.Close()
+		return writeErr
+	}
+	syncErr := tmpfile.Sync()
+	if syncErr != nil {
+		tmpfile.Close()
+		return syncErr
+	}
+	closeErr := tmpfile.Close()
+	if closeErr != nil {
+		return closeErr
+	}
+
+	return os.Rename(tmpfile.Name(), filename)
+}
@@ -0,0 +1,451 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// fakeStarknetNode is an in-memory Starknet JSON-RPC node which serves the methods the crawler uses:
+// starknet_blockNumber, starknet_getBlockWithTxHashes and starknet_getEvents. The hash of block n on fork f
+// is n*1000+f, so a reorganization is simulated by moving blocks to a different fork, and its timestamp is
+// n*10. It also answers starknet_call with the responses in calls, keyed by entry point selector, whatever
+// the block.
+type fakeStarknetNode struct {
+	mu     sync.Mutex
+	head   uint64
+	forks  map[uint64]uint64
+	events []rpc.EmittedEvent
+	calls  map[string][]*felt.Felt
+}
+
+func newFakeStarknetNode(t *testing.T, head uint64) (*fakeStarknetNode, *ProviderPool) {
+	node := &fakeStarknetNode{head: head, forks: make(map[uint64]uint64)}
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+
+	providers, poolErr := NewProviderPool([]string{server.URL}, RetryConfig{})
+	if poolErr != nil {
+		t.Fatalf("could not connect to fake node: %s", poolErr.Error())
+	}
+	return node, providers
+}
+
+func (node *fakeStarknetNode) blockHash(blockNumber uint64) *felt.Felt {
+	return new(felt.Felt).SetUint64(blockNumber*1000 + node.forks[blockNumber])
+}
+
+// Adds an event emitted by the given contract in the given transaction to the given block.
+func (node *fakeStarknetNode) addEvent(blockNumber, transactionHash, fromAddress uint64) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	node.events = append(node.events, rpc.EmittedEvent{
+		Event: rpc.Event{
+			FromAddress: new(felt.Felt).SetUint64(fromAddress),
+			Keys:        []*felt.Felt{new(felt.Felt).SetUint64(1)},
+			Data:        []*felt.Felt{},
+		},
+		BlockHash:       node.blockHash(blockNumber),
+		BlockNumber:     blockNumber,
+		TransactionHash: new(felt.Felt).SetUint64(transactionHash),
+	})
+}
+
+// Replaces every block from fromBlock onwards with a block on the given fork (dropping their events), and
+// moves the head of the chain to newHead.
+func (node *fakeStarknetNode) reorg(fromBlock, newHead, fork uint64) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	var kept []rpc.EmittedEvent
+	for _, event := range node.events {
+		if event.BlockNumber < fromBlock {
+			kept = append(kept, event)
+		}
+	}
+	node.events = kept
+	for blockNumber := fromBlock; blockNumber <= newHead || blockNumber <= node.head; blockNumber++ {
+		node.forks[blockNumber] = fork
+	}
+	node.head = newHead
+}
+
+func (node *fakeStarknetNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if decodeErr := json.NewDecoder(r.Body).Decode(&request); decodeErr != nil {
+		http.Error(w, decodeErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	node.mu.Lock()
+	result, rpcErr := node.handle(request.Method, request.Params)
+	node.mu.Unlock()
+
+	response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+	if rpcErr != nil {
+		response["error"] = rpcErr
+	} else {
+		response["result"] = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+type fakeRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (node *fakeStarknetNode) handle(method string, params []json.RawMessage) (interface{}, *fakeRPCError) {
+	blockNotFound := &fakeRPCError{Code: STARKNET_ERROR_BLOCK_NOT_FOUND, Message: "Block not found"}
+
+	switch method {
+	case "starknet_blockNumber":
+		return node.head, nil
+
+	case "starknet_getBlockWithTxHashes":
+		var blockID struct {
+			BlockNumber uint64 `json:"block_number"`
+		}
+		json.Unmarshal(params[0], &blockID)
+		if blockID.BlockNumber > node.head {
+			return nil, blockNotFound
+		}
+		zero := new(felt.Felt)
+		return rpc.BlockTxHashes{
+			BlockHeader: rpc.BlockHeader{
+				BlockHash:        node.blockHash(blockID.BlockNumber),
+				ParentHash:       zero,
+				BlockNumber:      blockID.BlockNumber,
+				Timestamp:        blockID.BlockNumber * 10,
+				NewRoot:          zero,
+				SequencerAddress: zero,
+			},
+			Status:       rpc.BlockStatus_AcceptedOnL2,
+			Transactions: []*felt.Felt{},
+		}, nil
+
+	case "starknet_getEvents":
+		var input struct {
+			FromBlock struct {
+				BlockNumber uint64 `json:"block_number"`
+			} `json:"from_block"`
+			ToBlock struct {
+				BlockNumber uint64 `json:"block_number"`
+			} `json:"to_block"`
+			Address           *felt.Felt `json:"address"`
+			ChunkSize         int        `json:"chunk_size"`
+			ContinuationToken string     `json:"continuation_token"`
+		}
+		json.Unmarshal(params[0], &input)
+		if input.ToBlock.BlockNumber > node.head {
+			return nil, blockNotFound
+		}
+
+		var matching []rpc.EmittedEvent
+		for _, event := range node.events {
+			if event.BlockNumber < input.FromBlock.BlockNumber || event.BlockNumber > input.ToBlock.BlockNumber {
+				continue
+			}
+			if input.Address != nil && !input.Address.Equal(event.FromAddress) {
+				continue
+			}
+			matching = append(matching, event)
+		}
+
+		offset, _ := strconv.Atoi(input.ContinuationToken)
+		end := offset + input.ChunkSize
+		chunk := rpc.EventChunk{Events: []rpc.EmittedEvent{}}
+		if end < len(matching) {
+			chunk.ContinuationToken = strconv.Itoa(end)
+		} else {
+			end = len(matching)
+		}
+		chunk.Events = append(chunk.Events, matching[offset:end]...)
+		return chunk, nil
+
+	case "starknet_call":
+		var request rpc.FunctionCall
+		json.Unmarshal(params[0], &request)
+		response, ok := node.calls[request.EntryPointSelector.String()]
+		if !ok {
+			return nil, &fakeRPCError{Code: 40, Message: "Contract error"}
+		}
+		return response, nil
+	}
+
+	return nil, &fakeRPCError{Code: -32601, Message: "Method not found"}
+}
+
+// Receives the next batch from a crawl, failing the test if none arrives in time.
+func receiveBatch(t *testing.T, batches <-chan CrawlBatch) CrawlBatch {
+	t.Helper()
+	select {
+	case batch, ok := <-batches:
+		if !ok {
+			t.Fatal("crawl ended unexpectedly")
+		}
+		return batch
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a batch from the crawl")
+	}
+	return CrawlBatch{}
+}
+
+func TestCrawlContractEventsRetractsWhenReorgShortensChain(t *testing.T) {
+	node, providers := newFakeStarknetNode(t, 10)
+	node.addEvent(8, 0x80, 0x1)
+	node.addEvent(9, 0x90, 0x1)
+	node.addEvent(10, 0xa0, 0x1)
+
+	config := CrawlerConfig{
+		ContractAddresses: []string{"0x01"},
+		HotThreshold:      1,
+		HotInterval:       time.Millisecond,
+		ColdInterval:      time.Millisecond,
+		BatchSize:         100,
+		ReorgDepth:        5,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	batches := make(chan CrawlBatch)
+	crawlErrChan := make(chan error, 1)
+	go func() {
+		crawlErrChan <- CrawlContractEvents(ctx, providers, config, CrawlCursor{FromBlock: 5}, batches)
+	}()
+
+	first := receiveBatch(t, batches)
+	if len(first.Events) != 3 || len(first.Retracted) != 0 {
+		t.Fatalf("expected 3 events and no retractions in the first batch, got %d events and %d retractions", len(first.Events), len(first.Retracted))
+	}
+
+	// Blocks 9 and 10 are replaced and the new head is block 8, so the crawl goes cold straight after it
+	// detects the reorganization.
+	node.reorg(9, 8, 1)
+
+	second := receiveBatch(t, batches)
+	if len(second.Events) != 0 {
+		t.Fatalf("expected no events after the reorganization, got %d", len(second.Events))
+	}
+	if len(second.Retracted) != 2 {
+		t.Fatalf("expected 2 retractions, got %d", len(second.Retracted))
+	}
+	for i, expectedBlock := range []uint64{9, 10} {
+		retracted := second.Retracted[i]
+		if retracted.BlockNumber != expectedBlock || !retracted.BlockHash.Equal(new(felt.Felt).SetUint64(expectedBlock*1000)) {
+			t.Errorf("retraction %d: expected block %d, got block %d (hash %s)", i, expectedBlock, retracted.BlockNumber, retracted.BlockHash.String())
+		}
+	}
+	if second.Next.FromBlock != 9 {
+		t.Errorf("expected the crawl to resume from block 9, got %d", second.Next.FromBlock)
+	}
+	for _, recentBlock := range second.Next.RecentBlocks {
+		if recentBlock.Number > 8 {
+			t.Errorf("cursor still remembers replaced block %d", recentBlock.Number)
+		}
+	}
+
+	cancel()
+	for range batches {
+	}
+	if crawlErr := <-crawlErrChan; crawlErr != nil {
+		t.Fatalf("crawl failed: %s", crawlErr.Error())
+	}
+}
+
+// Describes each event by its block number, transaction hash and emitting contract.
+func eventPositions(events []RawEvent) [][3]uint64 {
+	positions := make([][3]uint64, len(events))
+	for i, event := range events {
+		positions[i] = [3]uint64{event.BlockNumber, event.TransactionHash.Uint64(), event.FromAddress.Uint64()}
+	}
+	return positions
+}
+
+func TestInterleaveEvents(t *testing.T) {
+	cases := []struct {
+		name             string
+		eventsByContract [][]RawEvent
+		expected         [][3]uint64
+	}{
+		{
+			name:             "single contract",
+			eventsByContract: [][]RawEvent{{testRawEvent(7, 0x71, 0xa), testRawEvent(5, 0x51, 0xa)}},
+			expected:         [][3]uint64{{7, 0x71, 0xa}, {5, 0x51, 0xa}},
+		},
+		{
+			name: "different blocks",
+			eventsByContract: [][]RawEvent{
+				{testRawEvent(5, 0x51, 0xa), testRawEvent(7, 0x71, 0xa)},
+				{testRawEvent(4, 0x41, 0xb), testRawEvent(6, 0x61, 0xb), testRawEvent(8, 0x81, 0xb)},
+			},
+			expected: [][3]uint64{{4, 0x41, 0xb}, {5, 0x51, 0xa}, {6, 0x61, 0xb}, {7, 0x71, 0xa}, {8, 0x81, 0xb}},
+		},
+		{
+			name: "transaction emitting from both contracts",
+			eventsByContract: [][]RawEvent{
+				{testRawEvent(5, 0x51, 0xa), testRawEvent(5, 0x51, 0xa)},
+				{testRawEvent(5, 0x51, 0xb)},
+			},
+			expected: [][3]uint64{{5, 0x51, 0xa}, {5, 0x51, 0xa}, {5, 0x51, 0xb}},
+		},
+		{
+			name: "transactions ordered by first event",
+			eventsByContract: [][]RawEvent{
+				{testRawEvent(5, 0x52, 0xa), testRawEvent(5, 0x53, 0xa)},
+				{testRawEvent(5, 0x51, 0xb), testRawEvent(5, 0x53, 0xb)},
+			},
+			expected: [][3]uint64{{5, 0x52, 0xa}, {5, 0x53, 0xa}, {5, 0x53, 0xb}, {5, 0x51, 0xb}},
+		},
+		{
+			name:             "no events",
+			eventsByContract: [][]RawEvent{{}, {}},
+			expected:         [][3]uint64{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			interleaved := eventPositions(InterleaveEvents(c.eventsByContract))
+			if !reflect.DeepEqual(interleaved, c.expected) {
+				t.Errorf("expected events %v, got %v", c.expected, interleaved)
+			}
+		})
+	}
+}
+
+func TestCheckForReorg(t *testing.T) {
+	cases := []struct {
+		name string
+		// Arguments to fakeStarknetNode.reorg, if the chain is reorganized.
+		reorg           []uint64
+		retractedBlocks []uint64
+		fromBlock       uint64
+		err             error
+	}{
+		{name: "no reorganization", retractedBlocks: nil, fromBlock: 12},
+		{name: "replaced blocks", reorg: []uint64{9, 10, 1}, retractedBlocks: []uint64{9, 10}, fromBlock: 9},
+		{name: "shortened chain", reorg: []uint64{10, 9, 1}, retractedBlocks: []uint64{10}, fromBlock: 10},
+		{name: "replaced block without events", reorg: []uint64{11, 12, 1}, retractedBlocks: nil, fromBlock: 11},
+		{name: "too deep", reorg: []uint64{6, 10, 1}, err: ErrReorgTooDeep},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			node, providers := newFakeStarknetNode(t, 12)
+			if c.reorg != nil {
+				node.reorg(c.reorg[0], c.reorg[1], c.reorg[2])
+			}
+
+			// The crawl has emitted an event from each of blocks 8, 9 and 10, and crawled up to block 11.
+			cursor := CrawlCursor{FromBlock: 12}
+			for blockNumber := uint64(8); blockNumber <= 10; blockNumber++ {
+				cursor.recordEvent(testRawEvent(blockNumber, blockNumber*0x10, 0xa), 5)
+			}
+			cursor.recordBlock(11, new(felt.Felt).SetUint64(11000), 5)
+
+			retracted, reorgErr := CheckForReorg(context.Background(), providers, &cursor)
+			if c.err != nil {
+				if !errors.Is(reorgErr, c.err) {
+					t.Fatalf("expected error %v, got %v", c.err, reorgErr)
+				}
+				return
+			}
+			if reorgErr != nil {
+				t.Fatalf("could not check for reorganization: %s", reorgErr.Error())
+			}
+
+			var retractedBlocks []uint64
+			for _, event := range retracted {
+				if !event.BlockHash.Equal(new(felt.Felt).SetUint64(event.BlockNumber*1000)) || event.TransactionHash.Uint64() != event.BlockNumber*0x10 {
+					t.Errorf("retracted event %+v does not match the event emitted from block %d", event, event.BlockNumber)
+				}
+				retractedBlocks = append(retractedBlocks, event.BlockNumber)
+			}
+			if !reflect.DeepEqual(retractedBlocks, c.retractedBlocks) {
+				t.Errorf("expected events from blocks %v to be retracted, got %v", c.retractedBlocks, retractedBlocks)
+			}
+			if cursor.FromBlock != c.fromBlock {
+				t.Errorf("expected the cursor to resume from block %d, got %d", c.fromBlock, cursor.FromBlock)
+			}
+			lastRecent := cursor.RecentBlocks[len(cursor.RecentBlocks)-1]
+			if lastRecent.Number != c.fromBlock-1 {
+				t.Errorf("expected block %d to be the most recent block the cursor remembers, got %d", c.fromBlock-1, lastRecent.Number)
+			}
+		})
+	}
+}
+
+func TestCrawlContractEventsCheckpointStaysBounded(t *testing.T) {
+	// A single range of 200 blocks, with 3 events in each, is crawled 4 events at a time.
+	node, providers := newFakeStarknetNode(t, 200)
+	for blockNumber := uint64(1); blockNumber <= 200; blockNumber++ {
+		for i := uint64(0); i < 3; i++ {
+			node.addEvent(blockNumber, blockNumber*0x10+i, 0x1)
+		}
+	}
+
+	config := CrawlerConfig{
+		ContractAddresses: []string{"0x01"},
+		HotThreshold:      1,
+		HotInterval:       time.Millisecond,
+		ColdInterval:      time.Millisecond,
+		ToBlock:           200,
+		BatchSize:         4,
+		ReorgDepth:        5,
+	}
+
+	batches := make(chan CrawlBatch)
+	crawlErrChan := make(chan error, 1)
+	go func() {
+		crawlErrChan <- CrawlContractEvents(context.Background(), providers, config, CrawlCursor{FromBlock: 1}, batches)
+	}()
+
+	checkpointFile := filepath.Join(t.TempDir(), "checkpoint.json")
+	var numEvents int
+	var maxSize int64
+	for batch := range batches {
+		numEvents += len(batch.Events)
+		if len(batch.Next.RecentBlocks) > int(config.ReorgDepth) {
+			t.Fatalf("after %d events, the cursor remembers %d blocks", numEvents, len(batch.Next.RecentBlocks))
+		}
+
+		if saveErr := SaveCrawlCheckpoint(checkpointFile, CrawlCheckpoint{Cursor: batch.Next}); saveErr != nil {
+			t.Fatalf("could not save checkpoint: %s", saveErr.Error())
+		}
+		info, statErr := os.Stat(checkpointFile)
+		if statErr != nil {
+			t.Fatal(statErr)
+		}
+		if info.Size() > maxSize {
+			maxSize = info.Size()
+		}
+	}
+	if crawlErr := <-crawlErrChan; crawlErr != nil {
+		t.Fatalf("crawl failed: %s", crawlErr.Error())
+	}
+
+	if numEvents != 600 {
+		t.Fatalf("expected 600 events, got %d", numEvents)
+	}
+	// The cursor holds the IDs of the events from at most 5 blocks.
+	if maxSize > 4096 {
+		t.Errorf("expected the checkpoint to stay under 4096 bytes, but it reached %d bytes", maxSize)
+	}
+}
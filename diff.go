@@ -0,0 +1,265 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Ways in which an entrant can differ between two leaderboards.
+var DIFF_ADDED string = "added"
+var DIFF_REMOVED string = "removed"
+var DIFF_CHANGED string = "changed"
+
+// LeaderboardDiff describes how a leaderboard changed from one run to the next. Entrants whose rank,
+// score, and points data are all unchanged are only counted.
+type LeaderboardDiff struct {
+	Added     int                    `json:"added"`
+	Removed   int                    `json:"removed"`
+	Changed   int                    `json:"changed"`
+	Unchanged int                    `json:"unchanged"`
+	Entries   []LeaderboardEntryDiff `json:"entries"`
+}
+
+// LeaderboardEntryDiff describes how a single entrant (identified by their address) differs between two
+// leaderboards. Ranks start at 1, and a rank of 0 means that the entrant is not on that leaderboard.
+type LeaderboardEntryDiff struct {
+	Status   string `json:"status"`
+	Address  string `json:"address"`
+	OldRank  int    `json:"old_rank"`
+	NewRank  int    `json:"new_rank"`
+	OldScore int    `json:"old_score"`
+	NewScore int    `json:"new_score"`
+	// Difference between the old and new scores.
+	ScoreDelta int `json:"score_delta"`
+	// Components of the points data which differ between the two leaderboards, by name.
+	PointsDataChanges map[string]PointsDataChange `json:"points_data_changes,omitempty"`
+	// The entrant's score on the new leaderboard, if they are on it.
+	Entry *LeaderboardScore `json:"-"`
+}
+
+// PointsDataChange is the old and new values of a component of an entrant's points data. A value is nil
+// if the component is missing from that leaderboard.
+type PointsDataChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// Reads a leaderboard (a JSON array of LeaderboardScore objects, as output by the leaderboard commands)
+// from the given file.
+func ReadLeaderboardFile(leaderboardFile string) ([]LeaderboardScore, error) {
+	contents, readErr := os.ReadFile(leaderboardFile)
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	var leaderboard []LeaderboardScore
+	unmarshalErr := json.Unmarshal(contents, &leaderboard)
+	if unmarshalErr != nil {
+		return nil, fmt.Errorf("could not parse leaderboard file %s: %w", leaderboardFile, unmarshalErr)
+	}
+	return leaderboard, nil
+}
+
+// Returns the rank of each entrant on the leaderboard, by address. If every entrant has a rank (see
+// RankLeaderboard), these are returned. Otherwise, entrants are ranked by score, from highest to lowest,
+// and entrants with equal scores share a rank.
+func LeaderboardRanks(leaderboard []LeaderboardScore) map[string]int {
+	ranks := make(map[string]int, len(leaderboard))
+
+	ranked := true
+	for _, score := range leaderboard {
+		if score.Rank <= 0 {
+			ranked = false
+			break
+		}
+		ranks[score.Address] = score.Rank
+	}
+	if ranked {
+		return ranks
+	}
+
+	scores := make([]int, len(leaderboard))
+	for i, score := range leaderboard {
+		scores[i] = score.Score
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(scores)))
+
+	for _, score := range leaderboard {
+		// The rank is one more than the number of entrants with higher scores.
+		ranks[score.Address] = sort.Search(len(scores), func(i int) bool { return scores[i] <= score.Score }) + 1
+	}
+	return ranks
+}
+
+// Compares two leaderboards, matching their entrants by address. Returns an error if an address appears
+// more than once on either leaderboard.
+func DiffLeaderboards(oldLeaderboard, newLeaderboard []LeaderboardScore) (LeaderboardDiff, error) {
+	diff := LeaderboardDiff{Entries: []LeaderboardEntryDiff{}}
+
+	oldEntries := make(map[string]LeaderboardScore, len(oldLeaderboard))
+	for _, score := range oldLeaderboard {
+		if _, ok := oldEntries[score.Address]; ok {
+			return diff, fmt.Errorf("address appears more than once on the old leaderboard: %s", score.Address)
+		}
+		oldEntries[score.Address] = score
+	}
+	newEntries := make(map[string]LeaderboardScore, len(newLeaderboard))
+	for _, score := range newLeaderboard {
+		if _, ok := newEntries[score.Address]; ok {
+			return diff, fmt.Errorf("address appears more than once on the new leaderboard: %s", score.Address)
+		}
+		newEntries[score.Address] = score
+	}
+
+	oldRanks := LeaderboardRanks(oldLeaderboard)
+	newRanks := LeaderboardRanks(newLeaderboard)
+
+	for i := range newLeaderboard {
+		newEntry := &newLeaderboard[i]
+		entryDiff := LeaderboardEntryDiff{
+			Address:  newEntry.Address,
+			NewRank:  newRanks[newEntry.Address],
+			NewScore: newEntry.Score,
+			Entry:    newEntry,
+		}
+
+		oldEntry, ok := oldEntries[newEntry.Address]
+		if !ok {
+			entryDiff.Status = DIFF_ADDED
+			entryDiff.ScoreDelta = newEntry.Score
+			entryDiff.PointsDataChanges = PointsDataChanges(nil, newEntry.PointsData)
+			diff.Added++
+			diff.Entries = append(diff.Entries, entryDiff)
+			continue
+		}
+
+		entryDiff.OldRank = oldRanks[oldEntry.Address]
+		entryDiff.OldScore = oldEntry.Score
+		entryDiff.ScoreDelta = newEntry.Score - oldEntry.Score
+		entryDiff.PointsDataChanges = PointsDataChanges(oldEntry.PointsData, newEntry.PointsData)
+		if entryDiff.OldRank == entryDiff.NewRank && entryDiff.ScoreDelta == 0 && len(entryDiff.PointsDataChanges) == 0 {
+			diff.Unchanged++
+			continue
+		}
+		entryDiff.Status = DIFF_CHANGED
+		diff.Changed++
+		diff.Entries = append(diff.Entries, entryDiff)
+	}
+
+	for _, oldEntry := range oldLeaderboard {
+		if _, ok := newEntries[oldEntry.Address]; ok {
+			continue
+		}
+		diff.Removed++
+		diff.Entries = append(diff.Entries, LeaderboardEntryDiff{
+			Status:            DIFF_REMOVED,
+			Address:           oldEntry.Address,
+			OldRank:           oldRanks[oldEntry.Address],
+			OldScore:          oldEntry.Score,
+			ScoreDelta:        -oldEntry.Score,
+			PointsDataChanges: PointsDataChanges(oldEntry.PointsData, nil),
+		})
+	}
+
+	// Entrants on the new leaderboard come first, in rank order, followed by the entrants who were removed
+	// from it, in their old rank order.
+	sort.SliceStable(diff.Entries, func(i, j int) bool {
+		a, b := diff.Entries[i], diff.Entries[j]
+		if (a.NewRank == 0) != (b.NewRank == 0) {
+			return a.NewRank != 0
+		}
+		if a.NewRank != b.NewRank {
+			return a.NewRank < b.NewRank
+		}
+		if a.OldRank != b.OldRank {
+			return a.OldRank < b.OldRank
+		}
+		return a.Address < b.Address
+	})
+
+	return diff, nil
+}
+
+// Returns the components of the points data which differ between the old and new points data.
+func PointsDataChanges(oldPointsData, newPointsData map[string]interface{}) map[string]PointsDataChange {
+	changes := make(map[string]PointsDataChange)
+	for component, oldValue := range oldPointsData {
+		newValue, ok := newPointsData[component]
+		if !ok || !reflect.DeepEqual(oldValue, newValue) {
+			changes[component] = PointsDataChange{Old: oldValue, New: newValue}
+		}
+	}
+	for component, newValue := range newPointsData {
+		if _, ok := oldPointsData[component]; !ok {
+			changes[component] = PointsDataChange{Old: nil, New: newValue}
+		}
+	}
+	return changes
+}
+
+// Returns the entries of the new leaderboard which were added or changed, which is what must be pushed
+// (without overwriting) to bring a copy of the old leaderboard up to date - apart from removing the
+// entrants who are no longer on the leaderboard.
+func (diff LeaderboardDiff) ChangedEntries() []LeaderboardScore {
+	changed := []LeaderboardScore{}
+	for _, entry := range diff.Entries {
+		if entry.Entry != nil {
+			changed = append(changed, *entry.Entry)
+		}
+	}
+	return changed
+}
+
+// Writes the diff as a human-readable table.
+func (diff LeaderboardDiff) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tADDRESS\tRANK\tSCORE\tPOINTS DATA")
+
+	formatRank := func(rank int) string {
+		if rank == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%d", rank)
+	}
+	formatValue := func(value interface{}) string {
+		if value == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%v", value)
+	}
+
+	for _, entry := range diff.Entries {
+		rank := fmt.Sprintf("%s -> %s", formatRank(entry.OldRank), formatRank(entry.NewRank))
+		if entry.OldRank != 0 && entry.NewRank != 0 && entry.OldRank != entry.NewRank {
+			rank += fmt.Sprintf(" (%+d)", entry.OldRank-entry.NewRank)
+		}
+		score := fmt.Sprintf("%d -> %d (%+d)", entry.OldScore, entry.NewScore, entry.ScoreDelta)
+		if entry.Status == DIFF_ADDED {
+			score = fmt.Sprintf("%d", entry.NewScore)
+		} else if entry.Status == DIFF_REMOVED {
+			score = fmt.Sprintf("%d", entry.OldScore)
+		}
+
+		components := make([]string, 0, len(entry.PointsDataChanges))
+		for component := range entry.PointsDataChanges {
+			components = append(components, component)
+		}
+		sort.Strings(components)
+		changes := make([]string, len(components))
+		for i, component := range components {
+			change := entry.PointsDataChanges[component]
+			changes[i] = fmt.Sprintf("%s: %s -> %s", component, formatValue(change.Old), formatValue(change.New))
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", entry.Status, entry.Address, rank, score, strings.Join(changes, ", "))
+	}
+
+	fmt.Fprintf(tw, "\n%d added, %d removed, %d changed, %d unchanged\n", diff.Added, diff.Removed, diff.Changed, diff.Unchanged)
+	return tw.Flush()
+}
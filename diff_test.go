@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestDiffLeaderboards(t *testing.T) {
+	cases := []struct {
+		name                               string
+		old, new                           []LeaderboardScore
+		added, removed, changed, unchanged int
+		// Each entry as "status address old_rank->new_rank score_delta".
+		entries []string
+		err     bool
+	}{
+		{
+			name: "ranked leaderboards",
+			old: []LeaderboardScore{
+				{Rank: 1, Address: "a", Score: 30},
+				{Rank: 2, Address: "b", Score: 20},
+				{Rank: 3, Address: "c", Score: 10},
+				{Rank: 4, Address: "e", Score: 1},
+			},
+			new: []LeaderboardScore{
+				{Rank: 1, Address: "b", Score: 35},
+				{Rank: 2, Address: "a", Score: 30},
+				{Rank: 3, Address: "d", Score: 5},
+				{Rank: 4, Address: "e", Score: 1},
+			},
+			added: 1, removed: 1, changed: 2, unchanged: 1,
+			entries: []string{"changed b 2->1 15", "changed a 1->2 0", "added d 0->3 5", "removed c 3->0 -10"},
+		},
+		{
+			name:    "points data",
+			old:     []LeaderboardScore{{Rank: 1, Address: "a", Score: 10, PointsData: map[string]interface{}{"SlayedBeast": 1, "FleeFailed": 0}}},
+			new:     []LeaderboardScore{{Rank: 1, Address: "a", Score: 10, PointsData: map[string]interface{}{"SlayedBeast": 1, "FleeSucceeded": 1}}},
+			changed: 1,
+			entries: []string{"changed a 1->1 0"},
+		},
+		{
+			name:      "unranked leaderboards",
+			old:       []LeaderboardScore{{Address: "a", Score: 10}, {Address: "b", Score: 10}, {Address: "c", Score: 5}},
+			new:       []LeaderboardScore{{Address: "c", Score: 10}, {Address: "a", Score: 10}, {Address: "b", Score: 10}},
+			changed:   1,
+			unchanged: 2,
+			entries:   []string{"changed c 3->1 5"},
+		},
+		{
+			name: "duplicate address",
+			old:  []LeaderboardScore{{Address: "a", Score: 10}},
+			new:  []LeaderboardScore{{Address: "a", Score: 10}, {Address: "a", Score: 5}},
+			err:  true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			diff, diffErr := DiffLeaderboards(c.old, c.new)
+			if c.err {
+				if diffErr == nil {
+					t.Fatal("expected the diff to fail")
+				}
+				return
+			}
+			if diffErr != nil {
+				t.Fatalf("could not diff leaderboards: %s", diffErr.Error())
+			}
+
+			if diff.Added != c.added || diff.Removed != c.removed || diff.Changed != c.changed || diff.Unchanged != c.unchanged {
+				t.Errorf("expected %d added, %d removed, %d changed and %d unchanged, got %d, %d, %d and %d", c.added, c.removed, c.changed, c.unchanged, diff.Added, diff.Removed, diff.Changed, diff.Unchanged)
+			}
+			entries := make([]string, len(diff.Entries))
+			for i, entry := range diff.Entries {
+				entries[i] = fmt.Sprintf("%s %s %d->%d %d", entry.Status, entry.Address, entry.OldRank, entry.NewRank, entry.ScoreDelta)
+			}
+			if !reflect.DeepEqual(entries, c.entries) {
+				t.Errorf("expected entries %v, got %v", c.entries, entries)
+			}
+		})
+	}
+}
+
+func TestDiffLeaderboardsPointsDataChanges(t *testing.T) {
+	old := []LeaderboardScore{{Rank: 1, Address: "a", Score: 10, PointsData: map[string]interface{}{"SlayedBeast": 1, "FleeFailed": 0}}}
+	new := []LeaderboardScore{{Rank: 1, Address: "a", Score: 10, PointsData: map[string]interface{}{"SlayedBeast": 1, "FleeSucceeded": 1}}}
+
+	diff, diffErr := DiffLeaderboards(old, new)
+	if diffErr != nil {
+		t.Fatalf("could not diff leaderboards: %s", diffErr.Error())
+	}
+	expected := map[string]PointsDataChange{
+		"FleeFailed":    {Old: 0, New: nil},
+		"FleeSucceeded": {Old: nil, New: 1},
+	}
+	if len(diff.Entries) != 1 || !reflect.DeepEqual(diff.Entries[0].PointsDataChanges, expected) {
+		t.Errorf("expected points data changes %v, got %+v", expected, diff.Entries)
+	}
+}
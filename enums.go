@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// EnumValue is the form in which "parse --decode-enums" writes an enum field: the raw value, together with
+// its name (see ENUM_FIELDS).
+type EnumValue struct {
+	Value uint64
+	Name  string
+}
+
+// Returns the name of a tier ("T1" to "T5", or "None").
+//
+// Note: EvaluateCombat_Constants_CombatEnums_Tier cannot be used for this. Seer subtracts 2 from the
+// numbers in the names it generates (as in "u6" for Cairo's u8, or SpecialDash1 for special1), so it names
+// the tiers "T-1" to "T3".
+func TierName(tier Combat_Constants_CombatEnums_Tier) string {
+	if tier == 0 {
+		return "None"
+	}
+	if tier > 5 {
+		return "UNKNOWN"
+	}
+	return fmt.Sprintf("T%d", tier)
+}
+
+// Fields of parsed events which hold enum values, and the functions which name their values. Each of these
+// field names is only used for a single enum in the LootSurvivor contract's events.
+var ENUM_FIELDS map[string]func(uint64) string = map[string]func(uint64) string{
+	"Tier":        TierName,
+	"ItemType":    EvaluateCombat_Constants_CombatEnums_Type,
+	"Slot":        EvaluateCombat_Constants_CombatEnums_Slot,
+	"CriticalHit": EvaluateCore_Bool,
+	"Mutated":     EvaluateCore_Bool,
+}
+
+// Rewrites a parsed event so that each of its enum fields (see ENUM_FIELDS), at any depth, holds an
+// EnumValue instead of a bare integer. The order of the event's fields is preserved.
+//
+// Note: The rewritten event can no longer be unmarshalled into the bindings' structs, so files written
+// with decoded enums are meant to be read by people rather than by the other commands.
+func DecodeEnums(event json.RawMessage) (json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(event))
+	decoder.UseNumber()
+
+	var buf bytes.Buffer
+	rewriteErr := rewriteEnums(decoder, "", &buf)
+	if rewriteErr != nil {
+		return nil, rewriteErr
+	}
+	return buf.Bytes(), nil
+}
+
+// Copies the next JSON value from the decoder to buf, replacing the value with an EnumValue if it is a
+// number in an enum field.
+func rewriteEnums(decoder *json.Decoder, field string, buf *bytes.Buffer) error {
+	token, tokenErr := decoder.Token()
+	if tokenErr == io.EOF {
+		return fmt.Errorf("unexpected end of event")
+	} else if tokenErr != nil {
+		return tokenErr
+	}
+
+	switch typedToken := token.(type) {
+	case json.Delim:
+		switch typedToken {
+		case '{':
+			buf.WriteByte('{')
+			for i := 0; decoder.More(); i++ {
+				keyToken, keyErr := decoder.Token()
+				if keyErr != nil {
+					return keyErr
+				}
+				key, ok := keyToken.(string)
+				if !ok {
+					return fmt.Errorf("invalid object key: %v", keyToken)
+				}
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				encodedKey, _ := json.Marshal(key)
+				buf.Write(encodedKey)
+				buf.WriteByte(':')
+				valueErr := rewriteEnums(decoder, key, buf)
+				if valueErr != nil {
+					return valueErr
+				}
+			}
+			buf.WriteByte('}')
+		case '[':
+			buf.WriteByte('[')
+			for i := 0; decoder.More(); i++ {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				elementErr := rewriteEnums(decoder, "", buf)
+				if elementErr != nil {
+					return elementErr
+				}
+			}
+			buf.WriteByte(']')
+		}
+		// Consumes the closing delimiter.
+		_, closeErr := decoder.Token()
+		return closeErr
+	case json.Number:
+		if evaluate, isEnum := ENUM_FIELDS[field]; isEnum {
+			value, parseErr := strconv.ParseUint(typedToken.String(), 10, 64)
+			if parseErr == nil {
+				encodedValue, marshalErr := json.Marshal(EnumValue{Value: value, Name: evaluate(value)})
+				if marshalErr != nil {
+					return marshalErr
+				}
+				buf.Write(encodedValue)
+				return nil
+			}
+		}
+		buf.WriteString(typedToken.String())
+	default:
+		encodedToken, marshalErr := json.Marshal(typedToken)
+		if marshalErr != nil {
+			return marshalErr
+		}
+		buf.Write(encodedToken)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeEnums(t *testing.T) {
+	cases := []struct {
+		name     string
+		event    string
+		expected string
+	}{
+		{
+			name:     "nested fields",
+			event:    `{"Id":12,"BeastSpecs":{"Tier":2,"ItemType":3,"Level":7},"CriticalHit":1}`,
+			expected: `{"Id":12,"BeastSpecs":{"Tier":{"Value":2,"Name":"T2"},"ItemType":{"Value":3,"Name":"Bludgeon_or_Metal"},"Level":7},"CriticalHit":{"Value":1,"Name":"True"}}`,
+		},
+		{
+			name:     "unknown values",
+			event:    `{"Tier":0,"Slot":99,"Mutated":2}`,
+			expected: `{"Tier":{"Value":0,"Name":"None"},"Slot":{"Value":99,"Name":"UNKNOWN"},"Mutated":{"Value":2,"Name":"UNKNOWN"}}`,
+		},
+		{
+			name:     "arrays",
+			event:    `{"Items":[{"Slot":1,"Tier":6},{"Slot":2}],"Seed":"0x1f"}`,
+			expected: `{"Items":[{"Slot":{"Value":1,"Name":"Weapon"},"Tier":{"Value":6,"Name":"UNKNOWN"}},{"Slot":{"Value":2,"Name":"Chest"}}],"Seed":"0x1f"}`,
+		},
+		{
+			// Values which are not non-negative integers are left as they are.
+			name:     "non-integer values",
+			event:    `{"Tier":"2","ItemType":-1,"Slot":null,"CriticalHit":1.5}`,
+			expected: `{"Tier":"2","ItemType":-1,"Slot":null,"CriticalHit":1.5}`,
+		},
+		{
+			name:     "large numbers",
+			event:    `{"Seed":123456789012345678901234567890}`,
+			expected: `{"Seed":123456789012345678901234567890}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			decoded, decodeErr := DecodeEnums(json.RawMessage(c.event))
+			if decodeErr != nil {
+				t.Fatalf("could not decode enums: %s", decodeErr.Error())
+			}
+			if string(decoded) != c.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", c.expected, string(decoded))
+			}
+		})
+	}
+
+	if _, decodeErr := DecodeEnums(json.RawMessage(`{"Tier":`)); decodeErr == nil {
+		t.Error("expected a truncated event to fail")
+	}
+}
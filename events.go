@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// Name under which the "stark events" command writes a retraction record. A retraction record states that
+// a previously written event (identified by BlockHash, TransactionHash, FromAddress and EventIndex) was
+// emitted from a block which has since been replaced in a chain reorganization, and should be disregarded.
+var EVENT_RETRACTED = "RETRACTED"
+
+// CrawledEvent is the format in which the "stark events" and "parse" commands write events (one JSON
+// object per line). It extends ParsedEvent with the position of the event on the blockchain, which the
+// parsed event structs do not carry themselves. If an event could not be parsed, it is written with Name
+// set to EVENT_UNKNOWN, its Event is the RawEvent, and ParseError describes why the parse failed. Names and
+// Derived are only filled in by "parse --names" and "parse --derived" (see EventNames and DerivedFields).
+type CrawledEvent struct {
+	Name            string
+	Event           interface{}
+	BlockNumber     uint64
+	BlockHash       *felt.Felt
+	TransactionHash *felt.Felt
+	FromAddress     *felt.Felt
+	EventIndex      uint64
+	ParseError      string            `json:",omitempty"`
+	Names           map[string]string `json:",omitempty"`
+	Derived         map[string]uint64 `json:",omitempty"`
+}
+
+// PartialCrawledEvent is a CrawledEvent whose Event has not been unmarshalled.
+type PartialCrawledEvent struct {
+	Name            string
+	Event           json.RawMessage
+	BlockNumber     uint64
+	BlockHash       *felt.Felt
+	TransactionHash *felt.Felt
+	FromAddress     *felt.Felt
+	EventIndex      uint64
+	ParseError      string            `json:",omitempty"`
+	Names           map[string]string `json:",omitempty"`
+	Derived         map[string]uint64 `json:",omitempty"`
+}
+
+// RawEventParser parses the raw events emitted by a contract. EventParser parses the events of the
+// LootSurvivor contract, and BeastsEventParser those of the Beasts NFT contract. Parse returns an event
+// named EVENT_UNKNOWN if it does not recognize the event.
+type RawEventParser interface {
+	Parse(event RawEvent) (ParsedEvent, error)
+}
+
+// EventParsers parses events with each of its parsers in turn, and returns the result of the first parser
+// which recognizes the event (or fails to parse it).
+type EventParsers []RawEventParser
+
+func (parsers EventParsers) Parse(event RawEvent) (ParsedEvent, error) {
+	result := ParsedEvent{Name: EVENT_UNKNOWN, Event: event}
+	for _, parser := range parsers {
+		parsedEvent, parseErr := parser.Parse(event)
+		if parseErr != nil || parsedEvent.Name != EVENT_UNKNOWN {
+			return parsedEvent, parseErr
+		}
+	}
+	return result, nil
+}
+
+// Returns a parser for the events of all the contracts that this tool knows: the LootSurvivor contract
+// and the Beasts NFT contract.
+func NewCrawledEventParser() (EventParsers, error) {
+	gameParser, gameErr := NewEventParser()
+	if gameErr != nil {
+		return nil, gameErr
+	}
+	beastsParser, beastsErr := NewBeastsEventParser()
+	if beastsErr != nil {
+		return nil, beastsErr
+	}
+	return EventParsers{gameParser, beastsParser}, nil
+}
+
+// Kinds of contract whose events this tool knows how to parse.
+var CONTRACT_GAME string = "game"
+var CONTRACT_BEASTS string = "beasts"
+
+// ContractSpec is a contract from which to crawl events, as given to "stark events --contract": the
+// contract's address, optionally preceded by its kind (CONTRACT_GAME or CONTRACT_BEASTS) and "=", e.g.
+// "beasts=0x0158160018d590d93528995b340260e65aedd76d28a686e9daa5c4e8fad0c5dd".
+type ContractSpec struct {
+	Kind    string
+	Address string
+}
+
+// Parses a contract specification of the form "[KIND=]ADDRESS" (see ContractSpec).
+func ParseContractSpec(spec string) (ContractSpec, error) {
+	result := ContractSpec{Address: spec}
+	if kind, address, found := strings.Cut(spec, "="); found {
+		if kind != CONTRACT_GAME && kind != CONTRACT_BEASTS {
+			return result, fmt.Errorf("unknown kind of contract %q in %s (expected %s or %s)", kind, spec, CONTRACT_GAME, CONTRACT_BEASTS)
+		}
+		result.Kind = kind
+		result.Address = address
+	}
+	if _, addressErr := NormalizeAddress(result.Address); addressErr != nil {
+		return result, addressErr
+	}
+	return result, nil
+}
+
+// ContractEventParser parses each event with the parser for the contract which emitted it, chosen by the
+// event's FromAddress. Parsers is keyed by normalized address (see NormalizeAddress). Events emitted by
+// other contracts are parsed by Default.
+type ContractEventParser struct {
+	Parsers map[string]RawEventParser
+	Default RawEventParser
+}
+
+func (parser ContractEventParser) Parse(event RawEvent) (ParsedEvent, error) {
+	if event.FromAddress != nil {
+		address, addressErr := NormalizeAddress(event.FromAddress.String())
+		if addressErr == nil {
+			if contractParser, ok := parser.Parsers[address]; ok {
+				return contractParser.Parse(event)
+			}
+		}
+	}
+	return parser.Default.Parse(event)
+}
+
+// Returns a parser which parses the events of each of the given contracts whose kind is specified with the
+// parser for that kind of contract. All other events are parsed by the parser that NewCrawledEventParser
+// returns.
+func NewContractEventParser(contracts []ContractSpec) (ContractEventParser, error) {
+	result := ContractEventParser{Parsers: make(map[string]RawEventParser)}
+
+	gameParser, gameErr := NewEventParser()
+	if gameErr != nil {
+		return result, gameErr
+	}
+	beastsParser, beastsErr := NewBeastsEventParser()
+	if beastsErr != nil {
+		return result, beastsErr
+	}
+	result.Default = EventParsers{gameParser, beastsParser}
+
+	for _, contract := range contracts {
+		address, addressErr := NormalizeAddress(contract.Address)
+		if addressErr != nil {
+			return result, addressErr
+		}
+		switch contract.Kind {
+		case CONTRACT_GAME:
+			result.Parsers[address] = gameParser
+		case CONTRACT_BEASTS:
+			result.Parsers[address] = beastsParser
+		}
+	}
+
+	return result, nil
+}
+
+// Wraps a raw event as a CrawledEvent without parsing it.
+func UnparsedCrawledEvent(event RawEvent, eventIndex uint64) CrawledEvent {
+	return CrawledEvent{
+		Name:            EVENT_UNKNOWN,
+		Event:           event,
+		BlockNumber:     event.BlockNumber,
+		BlockHash:       event.BlockHash,
+		TransactionHash: event.TransactionHash,
+		FromAddress:     event.FromAddress,
+		EventIndex:      eventIndex,
+	}
+}
+
+// Parses a raw event using the given parser. If the parse fails, the returned CrawledEvent wraps the raw
+// event as an EVENT_UNKNOWN and records the reason for the failure.
+func ParseCrawledEvent(parser RawEventParser, event RawEvent, eventIndex uint64) CrawledEvent {
+	result := UnparsedCrawledEvent(event, eventIndex)
+
+	if event.PrimaryKey == nil {
+		result.ParseError = "event has no keys"
+		return result
+	}
+
+	parsedEvent, parseErr := parser.Parse(event)
+	if parseErr != nil {
+		result.ParseError = parseErr.Error()
+		return result
+	}
+
+	if parsedEvent.Name == EVENT_UNKNOWN {
+		result.ParseError = fmt.Sprintf("unrecognized event key: %s", event.PrimaryKey.String())
+		return result
+	}
+
+	result.Name = parsedEvent.Name
+	result.Event = parsedEvent.Event
+	return result
+}
+
+// Creates the retraction record for an event that the crawler emitted from a replaced block.
+func RetractionRecord(retracted RetractedEvent) CrawledEvent {
+	return CrawledEvent{
+		Name:            EVENT_RETRACTED,
+		BlockNumber:     retracted.BlockNumber,
+		BlockHash:       retracted.BlockHash,
+		TransactionHash: retracted.TransactionHash,
+		FromAddress:     retracted.FromAddress,
+		EventIndex:      retracted.EventIndex,
+	}
+}
+
+// Returns a reader over the lines of the given events file (as produced by the "stark events" command)
+// with retracted events, and the retraction records themselves, removed.
+//
+// A retraction record always refers to an event from one of the reorgDepth blocks preceding the most
+// recent block in the file at the point where the record appears. The returned reader therefore holds
+// each event back until reorgDepth more blocks have been seen (or the file ends), at which point it can
+// no longer be retracted. Lines which carry no block information are held back in order with the others.
+// The relative order of the remaining lines is preserved.
+func WithoutRetractedEvents(eventsFile io.Reader, reorgDepth uint64) io.Reader {
+	type heldLine struct {
+		line        []byte
+		blockNumber uint64
+		key         string
+	}
+
+	eventKey := func(event PartialCrawledEvent) string {
+		if event.BlockHash == nil || event.TransactionHash == nil || event.FromAddress == nil {
+			return ""
+		}
+		return fmt.Sprintf("%s:%s:%s:%d", event.BlockHash.String(), event.TransactionHash.String(), event.FromAddress.String(), event.EventIndex)
+	}
+
+	reader, writer := io.Pipe()
+
+	go func() {
+		var held []heldLine
+		var latestBlock uint64
+
+		release := func(all bool) error {
+			released := 0
+			for _, h := range held {
+				if !all && h.key != "" && h.blockNumber+reorgDepth > latestBlock {
+					break
+				}
+				if h.key != "-" {
+					_, writeErr := writer.Write(append(h.line, '\n'))
+					if writeErr != nil {
+						return writeErr
+					}
+				}
+				released++
+			}
+			held = held[released:]
+			return nil
+		}
+
+		scanner := bufio.NewScanner(eventsFile)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			var event PartialCrawledEvent
+			unmarshalErr := json.Unmarshal(line, &event)
+			if unmarshalErr != nil {
+				writer.CloseWithError(unmarshalErr)
+				return
+			}
+
+			key := eventKey(event)
+
+			if event.Name == EVENT_RETRACTED {
+				found := false
+				for i := range held {
+					if held[i].key == key {
+						// Marks the line as dropped.
+						held[i].key = "-"
+						found = true
+						break
+					}
+				}
+				if !found {
+					writer.CloseWithError(fmt.Errorf("retracted event (%s) is not among the events from the last %d blocks", key, reorgDepth))
+					return
+				}
+				continue
+			}
+
+			if key != "" && event.BlockNumber > latestBlock {
+				latestBlock = event.BlockNumber
+			}
+			held = append(held, heldLine{line: line, blockNumber: event.BlockNumber, key: key})
+
+			releaseErr := release(false)
+			if releaseErr != nil {
+				writer.CloseWithError(releaseErr)
+				return
+			}
+		}
+		scanErr := scanner.Err()
+		if scanErr != nil {
+			writer.CloseWithError(scanErr)
+			return
+		}
+
+		writer.CloseWithError(release(true))
+	}()
+
+	return reader
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Returns the retraction record for the event with the given index among those its contract emitted in
+// its transaction.
+func retractionLine(t *testing.T, event RawEvent, eventIndex uint64) []byte {
+	t.Helper()
+	line, marshalErr := json.Marshal(RetractionRecord(RetractedEvent{
+		BlockNumber: event.BlockNumber,
+		BlockHash:   event.BlockHash,
+		EventID:     EventID{TransactionHash: event.TransactionHash, FromAddress: event.FromAddress, EventIndex: eventIndex},
+	}))
+	if marshalErr != nil {
+		t.Fatalf("could not marshal retraction record: %s", marshalErr.Error())
+	}
+	return line
+}
+
+func TestWithoutRetractedEvents(t *testing.T) {
+	// Block 6 holds an event from each of two contracts in the same transaction, and is replaced along with
+	// block 7.
+	original := crawledLines(t, []RawEvent{
+		testRawEvent(5, 0x51, 0xa),
+		testRawEvent(6, 0x61, 0xa),
+		testRawEvent(6, 0x61, 0xb),
+		testRawEvent(7, 0x71, 0xa),
+	})
+	replacement := crawledLines(t, []RawEvent{testRawEvent(7, 0x72, 0xa), testRawEvent(9, 0x91, 0xa)})
+
+	cases := []struct {
+		name       string
+		reorgDepth uint64
+		lines      [][]byte
+		expected   [][]byte
+		err        bool
+	}{
+		{
+			name:       "no retractions",
+			reorgDepth: 2,
+			lines:      original,
+			expected:   original,
+		},
+		{
+			name:       "retracted events",
+			reorgDepth: 2,
+			lines: [][]byte{
+				original[0], original[1], original[2], original[3],
+				retractionLine(t, testRawEvent(6, 0x61, 0xa), 0),
+				retractionLine(t, testRawEvent(7, 0x71, 0xa), 0),
+				replacement[0], replacement[1],
+			},
+			expected: [][]byte{original[0], original[2], replacement[0], replacement[1]},
+		},
+		{
+			name:       "unknown event",
+			reorgDepth: 2,
+			lines:      [][]byte{original[0], retractionLine(t, testRawEvent(5, 0x51, 0xa), 1)},
+			err:        true,
+		},
+		{
+			name:       "event older than the reorganization depth",
+			reorgDepth: 1,
+			lines:      [][]byte{original[0], original[3], retractionLine(t, testRawEvent(5, 0x51, 0xa), 0)},
+			err:        true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			eventsFile := bytes.NewReader(append(bytes.Join(c.lines, []byte("\n")), '\n'))
+			contents, readErr := io.ReadAll(WithoutRetractedEvents(eventsFile, c.reorgDepth))
+			if c.err {
+				if readErr == nil {
+					t.Fatal("expected the retraction to fail")
+				}
+				return
+			}
+			if readErr != nil {
+				t.Fatalf("could not read events: %s", readErr.Error())
+			}
+
+			expected := string(append(bytes.Join(c.expected, []byte("\n")), '\n'))
+			if string(contents) != expected {
+				t.Errorf("expected events:\n%s\ngot:\n%s", expected, strings.TrimSpace(string(contents)))
+			}
+		})
+	}
+}
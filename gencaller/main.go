@@ -0,0 +1,332 @@
+// gencaller generates a typed Go client for the view functions of a Starknet contract from the contract's
+// ABI. The generated code belongs to the same package as the seer bindings for the contract (see
+// bindings.go), whose Parse* functions it uses to decode the return values of the view functions.
+//
+// Usage:
+//
+//	go run ./gencaller -abi abis/LootSurvivor.json -name LootSurvivor -o caller.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// ABIParameter is an input or output of a function in a Starknet ABI.
+type ABIParameter struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ABIItem is an item (function, interface, struct, ...) in a Starknet ABI. Only the fields which the
+// generator uses are decoded.
+type ABIItem struct {
+	Type            string         `json:"type"`
+	Name            string         `json:"name"`
+	Inputs          []ABIParameter `json:"inputs"`
+	Outputs         []ABIParameter `json:"outputs"`
+	StateMutability string         `json:"state_mutability"`
+	Items           []ABIItem      `json:"items"`
+}
+
+// GoType describes how values of a Cairo type are represented in Go.
+type GoType struct {
+	// Go type of the values.
+	Name string
+	// Expression for the function which parses a value of the type from a list of felts.
+	Parser string
+	// Kind of encoding with which a value of the type is passed as calldata (see ENCODE_*). Empty if values of
+	// the type cannot be passed as calldata.
+	Encoding string
+}
+
+// Ways in which arguments are encoded as calldata.
+var ENCODE_STRING string = "string"
+var ENCODE_UINT64 string = "uint64"
+var ENCODE_BIGINT string = "bigint"
+var ENCODE_U256 string = "u256"
+
+// Cairo types which seer represents with Go primitives. The integer types are named by the number of bits
+// of the values they hold, less 2.
+var PRIMITIVE_TYPES map[string]GoType = map[string]GoType{
+	"core::felt250": {Name: "string", Parser: "ParseString", Encoding: ENCODE_STRING},
+	"core::starknet::contract_address::ContractAddress": {Name: "string", Parser: "ParseString", Encoding: ENCODE_STRING},
+	"core::integer::u6":   {Name: "uint64", Parser: "ParseUint64", Encoding: ENCODE_UINT64},
+	"core::integer::u14":  {Name: "uint64", Parser: "ParseUint64", Encoding: ENCODE_UINT64},
+	"core::integer::u30":  {Name: "uint64", Parser: "ParseUint64", Encoding: ENCODE_UINT64},
+	"core::integer::u62":  {Name: "uint64", Parser: "ParseUint64", Encoding: ENCODE_UINT64},
+	"core::integer::u126": {Name: "*big.Int", Parser: "ParseBigInt", Encoding: ENCODE_BIGINT},
+	"core::integer::u254": {Name: "*big.Int", Parser: "ParseBigInt", Encoding: ENCODE_U256},
+	"core::bool":          {Name: "Core_Bool", Parser: "ParseCore_Bool", Encoding: ENCODE_UINT64},
+}
+
+// Returns the Go name that seer gives to a Cairo type, e.g. "Survivor_ItemMeta_ItemSpecials" for
+// "survivor::item_meta::ItemSpecials".
+func SeerTypeName(cairoType string) string {
+	segments := strings.Split(cairoType, "::")
+	for i, segment := range segments {
+		segments[i] = CamelCase(segment)
+	}
+	return strings.Join(segments, "_")
+}
+
+// Converts a snake_case identifier to CamelCase.
+func CamelCase(identifier string) string {
+	var result strings.Builder
+	for _, word := range strings.Split(identifier, "_") {
+		if word == "" {
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		result.WriteString(string(runes))
+	}
+	return result.String()
+}
+
+// Converts a snake_case identifier to lowerCamelCase.
+func LowerCamelCase(identifier string) string {
+	camel := []rune(CamelCase(identifier))
+	if len(camel) > 0 {
+		camel[0] = unicode.ToLower(camel[0])
+	}
+	return string(camel)
+}
+
+// Returns the Go representation of a Cairo type.
+func ResolveType(cairoType string) (GoType, error) {
+	if goType, ok := PRIMITIVE_TYPES[cairoType]; ok {
+		return goType, nil
+	}
+
+	if strings.HasPrefix(cairoType, "core::array::Array::<") && strings.HasSuffix(cairoType, ">") {
+		elementType, elementErr := ResolveType(strings.TrimSuffix(strings.TrimPrefix(cairoType, "core::array::Array::<"), ">"))
+		if elementErr != nil {
+			return GoType{}, elementErr
+		}
+		return GoType{Name: "[]" + elementType.Name, Parser: fmt.Sprintf("ParseArray[%s](%s)", elementType.Name, elementType.Parser)}, nil
+	}
+
+	if strings.HasPrefix(cairoType, "core::") || strings.HasPrefix(cairoType, "(") {
+		return GoType{}, fmt.Errorf("unsupported type: %s", cairoType)
+	}
+
+	name := SeerTypeName(cairoType)
+	return GoType{Name: name, Parser: "Parse" + name}, nil
+}
+
+// Splits the types of a function's outputs into the types of the values it returns. A tuple output, e.g.
+// "(core::bool, core::integer::u14)", is returned as a value for each of its members.
+func OutputTypes(outputs []ABIParameter) []string {
+	var types []string
+	for _, output := range outputs {
+		if strings.HasPrefix(output.Type, "(") && strings.HasSuffix(output.Type, ")") {
+			for _, member := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(output.Type, "("), ")"), ",") {
+				types = append(types, strings.TrimSpace(member))
+			}
+		} else {
+			types = append(types, output.Type)
+		}
+	}
+	return types
+}
+
+// Returns the view functions in the ABI, including those declared in interfaces, in the order in which
+// they appear.
+func ViewFunctions(items []ABIItem) []ABIItem {
+	var views []ABIItem
+	for _, item := range items {
+		if item.Type == "interface" {
+			views = append(views, ViewFunctions(item.Items)...)
+		} else if item.Type == "function" && item.StateMutability == "view" {
+			views = append(views, item)
+		}
+	}
+	return views
+}
+
+// Writes the method of the caller which calls the given view function.
+func GenerateMethod(buf *bytes.Buffer, callerName string, function ABIItem) error {
+	methodName := CamelCase(function.Name)
+
+	var outputTypes []GoType
+	for _, cairoType := range OutputTypes(function.Outputs) {
+		outputType, typeErr := ResolveType(cairoType)
+		if typeErr != nil {
+			return fmt.Errorf("output of %s: %w", function.Name, typeErr)
+		}
+		outputTypes = append(outputTypes, outputType)
+	}
+
+	var returnTypes, zeroResults []string
+	for i, outputType := range outputTypes {
+		returnTypes = append(returnTypes, outputType.Name)
+		zeroResults = append(zeroResults, fmt.Sprintf("result%d, ", i))
+	}
+	returnTypes = append(returnTypes, "error")
+	zeroReturn := strings.Join(zeroResults, "")
+
+	var params []string
+	var encoders []string
+	for _, input := range function.Inputs {
+		inputType, typeErr := ResolveType(input.Type)
+		if typeErr != nil {
+			return fmt.Errorf("input %s of %s: %w", input.Name, function.Name, typeErr)
+		}
+		paramName := LowerCamelCase(input.Name)
+		params = append(params, fmt.Sprintf("%s %s", paramName, inputType.Name))
+
+		switch inputType.Encoding {
+		case ENCODE_STRING:
+			encoders = append(encoders, fmt.Sprintf(`	%[1]sFelt, %[1]sErr := new(felt.Felt).SetString(%[1]s)
+	if %[1]sErr != nil {
+		return %[3]sfmt.Errorf("invalid %[2]s: %%w", %[1]sErr)
+	}
+	calldata = append(calldata, %[1]sFelt)
+`, paramName, input.Name, zeroReturn))
+		case ENCODE_UINT64:
+			encoders = append(encoders, fmt.Sprintf("\tcalldata = append(calldata, new(felt.Felt).SetUint64(uint64(%s)))\n", paramName))
+		case ENCODE_BIGINT:
+			encoders = append(encoders, fmt.Sprintf("\tcalldata = append(calldata, new(felt.Felt).SetBigInt(%s))\n", paramName))
+		case ENCODE_U256:
+			encoders = append(encoders, fmt.Sprintf("\tcalldata = append(calldata, EncodeU256(%s)...)\n", paramName))
+		default:
+			return fmt.Errorf("input %s of %s: cannot encode values of type %s as calldata", input.Name, function.Name, input.Type)
+		}
+	}
+
+	fmt.Fprintf(buf, "// %s calls the %s view function of the contract at the given block.\n", methodName, function.Name)
+	fmt.Fprintf(buf, "func (caller *%s) %s(%s) (%s) {\n", callerName, methodName, strings.Join(append([]string{"ctx context.Context", "blockID rpc.BlockID"}, params...), ", "), strings.Join(returnTypes, ", "))
+	for i, outputType := range outputTypes {
+		fmt.Fprintf(buf, "\tvar result%d %s\n", i, outputType.Name)
+	}
+	buf.WriteString("\n\tcalldata := []*felt.Felt{}\n")
+	for _, encoder := range encoders {
+		buf.WriteString(encoder)
+	}
+	fmt.Fprintf(buf, "\n\tresponse, callErr := caller.Call(ctx, blockID, %q, calldata)\n", function.Name)
+	fmt.Fprintf(buf, "\tif callErr != nil {\n\t\treturn %scallErr\n\t}\n", zeroReturn)
+	if len(outputTypes) > 0 {
+		buf.WriteString("\n\tcurrentIndex := 0\n\tvar consumed int\n\tvar parseErr error\n")
+		for i, outputType := range outputTypes {
+			fmt.Fprintf(buf, "\tresult%d, consumed, parseErr = %s(response[currentIndex:])\n", i, outputType.Parser)
+			fmt.Fprintf(buf, "\tif parseErr != nil {\n\t\treturn %sfmt.Errorf(\"could not parse result of %s: %%w\", parseErr)\n\t}\n", zeroReturn, function.Name)
+			buf.WriteString("\tcurrentIndex += consumed\n")
+		}
+	}
+	fmt.Fprintf(buf, "\n\treturn %snil\n}\n\n", zeroReturn)
+
+	return nil
+}
+
+// Generates the source code of the caller for the view functions of the contract with the given ABI.
+func Generate(abi []ABIItem, contractName, packageName, abiFile string) ([]byte, error) {
+	callerName := contractName + "Caller"
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `// This file was generated by gencaller from %[1]s.
+// gencaller command: go run ./gencaller -abi %[1]s -name %[2]s -package %[3]s
+// Warning: Edit at your own risk. Any edits you make will NOT survive the next code generation.
+
+package %[3]s
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// %[4]s calls the view functions of a %[2]s contract through a Starknet RPC provider. Each view
+// function has a method, which takes the block at which to call the function (e.g. rpc.WithBlockTag("latest")
+// or rpc.WithBlockNumber(n)) followed by the function's arguments, and returns the function's results
+// decoded with the Parse* functions of the contract's bindings.
+type %[4]s struct {
+	Provider        *rpc.Provider
+	ContractAddress *felt.Felt
+}
+
+func New%[4]s(provider *rpc.Provider, contractAddress *felt.Felt) *%[4]s {
+	return &%[4]s{Provider: provider, ContractAddress: contractAddress}
+}
+
+// Calls a function of the contract at the given block, and returns the raw result.
+func (caller *%[4]s) Call(ctx context.Context, blockID rpc.BlockID, functionName string, calldata []*felt.Felt) ([]*felt.Felt, error) {
+	request := rpc.FunctionCall{
+		ContractAddress:    caller.ContractAddress,
+		EntryPointSelector: utils.GetSelectorFromNameFelt(functionName),
+		Calldata:           calldata,
+	}
+	response, callErr := caller.Provider.Call(ctx, request, blockID)
+	if callErr != nil {
+		return nil, fmt.Errorf("call to %%s failed: %%w", functionName, callErr)
+	}
+	return response, nil
+}
+
+// Encodes a 256-bit integer as calldata: its low 128 bits, followed by its high 128 bits.
+func EncodeU256(value *big.Int) []*felt.Felt {
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	low := new(big.Int).And(value, mask)
+	high := new(big.Int).Rsh(value, 128)
+	return []*felt.Felt{new(felt.Felt).SetBigInt(low), new(felt.Felt).SetBigInt(high)}
+}
+
+`, abiFile, contractName, packageName, callerName)
+
+	for _, function := range ViewFunctions(abi) {
+		methodErr := GenerateMethod(&buf, callerName, function)
+		if methodErr != nil {
+			return nil, methodErr
+		}
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+func main() {
+	var abiFile, contractName, packageName, outfile string
+	flag.StringVar(&abiFile, "abi", "", "ABI file of the contract")
+	flag.StringVar(&contractName, "name", "", "Name of the contract, which prefixes the name of the caller type")
+	flag.StringVar(&packageName, "package", "main", "Package of the generated code")
+	flag.StringVar(&outfile, "o", "", "File to write the generated code to (defaults to stdout)")
+	flag.Parse()
+
+	if abiFile == "" || contractName == "" {
+		log.Fatal("you must specify the ABI file (-abi) and the contract name (-name)")
+	}
+
+	contents, readErr := os.ReadFile(abiFile)
+	if readErr != nil {
+		log.Fatal(readErr)
+	}
+	var abi []ABIItem
+	unmarshalErr := json.Unmarshal(contents, &abi)
+	if unmarshalErr != nil {
+		log.Fatal(unmarshalErr)
+	}
+
+	code, generateErr := Generate(abi, contractName, packageName, abiFile)
+	if generateErr != nil {
+		log.Fatal(generateErr)
+	}
+
+	if outfile == "" {
+		os.Stdout.Write(code)
+		return
+	}
+	writeErr := os.WriteFile(outfile, code, 0644)
+	if writeErr != nil {
+		log.Fatal(writeErr)
+	}
+}
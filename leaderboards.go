@@ -4,60 +4,29 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
-	"net/http"
-	"net/url"
 	"os"
 	"strings"
 )
 
-var LEADERBOARDS_API_URL string = "https://engineapi.moonstream.to/leaderboard/%s/scores"
+// Ways in which the total leaderboard can aggregate scores: per adventurer, or per owner of the
+// adventurers.
+var AGGREGATE_BY_ADVENTURER string = "adventurer"
+var AGGREGATE_BY_OWNER string = "owner"
+
+var ErrInvalidAggregation error = errors.New("invalid aggregation (expected \"adventurer\" or \"owner\")")
 
 type LeaderboardScore struct {
+	// Position of the entrant on the leaderboard, starting at 1 (see RankLeaderboard).
+	Rank       int                    `json:"rank"`
 	Address    string                 `json:"address"`
 	Score      int                    `json:"score"`
 	PointsData map[string]interface{} `json:"points_data"`
-}
-
-func Push(leaderboardID, accessToken string, leaderboard []LeaderboardScore, overwrite bool) error {
-	leaderboardURL := fmt.Sprintf(LEADERBOARDS_API_URL, leaderboardID)
-	u, parseErr := url.Parse(leaderboardURL)
-	if parseErr != nil {
-		return parseErr
-	}
-	queryParams := url.Values{}
-	queryParams.Set("normalize_addresses", "false")
-	if overwrite {
-		queryParams.Set("overwrite", "true")
-	} else {
-		queryParams.Set("overwrite", "false")
-	}
-	u.RawQuery = queryParams.Encode()
-
-	var buf bytes.Buffer
-	encoder := json.NewEncoder(&buf)
-	encodeErr := encoder.Encode(leaderboard)
-	if encodeErr != nil {
-		return encodeErr
-	}
-
-	req, setupErr := http.NewRequest("PUT", u.String(), &buf)
-	if setupErr != nil {
-		return setupErr
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
-
-	httpClient := &http.Client{}
-	resp, apiErr := httpClient.Do(req)
-	if apiErr != nil {
-		return apiErr
-	}
-	defer resp.Body.Close()
-
-	fmt.Fprintf(os.Stderr, "Status: %d\n", resp.StatusCode)
-	return nil
+	// Data by which the entrant is ordered among entrants with the same score. This is not output.
+	TieBreakers TieBreakers `json:"-"`
 }
 
 var TotalLeaderboardEventScores map[string]int = map[string]int{
@@ -80,206 +49,683 @@ var TotalLeaderboardEventScores map[string]int = map[string]int{
 	"MaxLevelOfBeastSlayed":             0,
 }
 
-func LootSurvivorLeaderboard(eventsFile *os.File) ([]LeaderboardScore, error) {
+// Names of the events that LootSurvivorLeaderboard uses.
+var LootSurvivorLeaderboardEvents []string = []string{
+	Event_Game_Game_DiscoveredHealth,
+	Event_Game_Game_DiscoveredGold,
+	Event_Game_Game_DiscoveredBeast,
+	Event_Game_Game_DodgedObstacle,
+	Event_Game_Game_HitByObstacle,
+	Event_Game_Game_AmbushedByBeast,
+	Event_Game_Game_SlayedBeast,
+	Event_Game_Game_FleeFailed,
+	Event_Game_Game_FleeSucceeded,
+	Event_Game_Game_PurchasedItems,
+	Event_Game_Game_PurchasedPotions,
+	Event_Game_Game_AdventurerLeveledUp,
+	Event_Game_Game_AdventurerUpgraded,
+	Event_Game_Game_IdleDeathPenalty,
+	Event_Game_Game_AdventurerDied,
+	Event_Game_Game_StartGame,
+}
+
+// LootSurvivorLeaderboardState is the aggregation state from which LootSurvivorLeaderboard is built. It
+// can be persisted between runs, so that a later run only needs to fold in the events which are new since
+// the last one (see IncrementalLootSurvivorLeaderboard).
+type LootSurvivorLeaderboardState struct {
+	// All the events up to and including this block have been folded into the state.
+	LastBlock uint64 `json:"last_block"`
+	// The scoring with which the events were folded into the state.
+	Scoring TotalLeaderboardScoring `json:"scoring"`
 	// Score component -> adventurer -> value
-	subscores := map[string]map[string]int{
-		Event_Game_Game_DiscoveredHealth:    make(map[string]int),
-		Event_Game_Game_DiscoveredGold:      make(map[string]int),
-		Event_Game_Game_DiscoveredBeast:     make(map[string]int),
-		Event_Game_Game_DodgedObstacle:      make(map[string]int),
-		Event_Game_Game_HitByObstacle:       make(map[string]int),
-		Event_Game_Game_AmbushedByBeast:     make(map[string]int),
-		Event_Game_Game_SlayedBeast:         make(map[string]int),
-		Event_Game_Game_FleeFailed:          make(map[string]int),
-		Event_Game_Game_FleeSucceeded:       make(map[string]int),
-		Event_Game_Game_PurchasedItems:      make(map[string]int),
-		Event_Game_Game_PurchasedPotions:    make(map[string]int),
-		Event_Game_Game_AdventurerLeveledUp: make(map[string]int),
-		Event_Game_Game_AdventurerUpgraded:  make(map[string]int),
-		Event_Game_Game_IdleDeathPenalty:    make(map[string]int),
-		Event_Game_Game_AdventurerDied:      make(map[string]int),
-		"MaxLevelOfBeastSlayed":             make(map[string]int),
+	Subscores map[string]map[string]int `json:"subscores"`
+	// Event name -> adventurer -> points scored for the event
+	Points       map[string]map[string]int `json:"points"`
+	ActiveOwners map[string]string         `json:"active_owners"`
+	Names        map[string]string         `json:"names"`
+	// Adventurer -> block in which the adventurer's score last changed
+	ScoreBlocks map[string]uint64 `json:"score_blocks"`
+	// Adventurer -> XP as of the adventurer's latest event
+	XP map[string]uint64 `json:"xp"`
+}
+
+func NewLootSurvivorLeaderboardState(scoring TotalLeaderboardScoring) *LootSurvivorLeaderboardState {
+	return &LootSurvivorLeaderboardState{
+		Scoring:      scoring,
+		Subscores:    make(map[string]map[string]int),
+		Points:       make(map[string]map[string]int),
+		ActiveOwners: make(map[string]string),
+		Names:        make(map[string]string),
+		ScoreBlocks:  make(map[string]uint64),
+		XP:           make(map[string]uint64),
 	}
+}
 
-	names := make(map[string]string)
-	activeOwners := make(map[string]string)
-	scanner := bufio.NewScanner(eventsFile)
-	for scanner.Scan() {
-		line := scanner.Text()
-		var partialEvent PartialEvent
-		unmarshalErr := json.Unmarshal([]byte(line), &partialEvent)
+// Returns a deep copy of the state. The scoring is shared with the original.
+func (state *LootSurvivorLeaderboardState) Copy() *LootSurvivorLeaderboardState {
+	result := NewLootSurvivorLeaderboardState(state.Scoring)
+	result.LastBlock = state.LastBlock
+	for scoreComponent, data := range state.Subscores {
+		result.Subscores[scoreComponent] = make(map[string]int)
+		for adventurer, subscore := range data {
+			result.Subscores[scoreComponent][adventurer] = subscore
+		}
+	}
+	for eventName, data := range state.Points {
+		result.Points[eventName] = make(map[string]int)
+		for adventurer, points := range data {
+			result.Points[eventName][adventurer] = points
+		}
+	}
+	for adventurer, owner := range state.ActiveOwners {
+		result.ActiveOwners[adventurer] = owner
+	}
+	for adventurer, name := range state.Names {
+		result.Names[adventurer] = name
+	}
+	for adventurer, block := range state.ScoreBlocks {
+		result.ScoreBlocks[adventurer] = block
+	}
+	for adventurer, xp := range state.XP {
+		result.XP[adventurer] = xp
+	}
+	return result
+}
+
+func (state *LootSurvivorLeaderboardState) addSubscore(scoreComponent, adventurer string, value int) {
+	if _, ok := state.Subscores[scoreComponent]; !ok {
+		state.Subscores[scoreComponent] = make(map[string]int)
+	}
+	state.Subscores[scoreComponent][adventurer] += value
+}
+
+// Folds a single event, emitted in the given block, into the state. Events must be folded in the order in
+// which they were emitted.
+func (state *LootSurvivorLeaderboardState) FoldEvent(eventName string, rawEvent json.RawMessage, blockNumber uint64) error {
+	if _, scored := state.Scoring.Events[eventName]; !scored && eventName != Event_Game_Game_StartGame {
+		return nil
+	}
+
+	// The adventurer that the event concerns, and the number of times that the event counts towards their
+	// subscore for it.
+	var adventurer string
+	units := 1
+
+	if eventName == Event_Game_Game_DiscoveredHealth {
+		var event Game_Game_DiscoveredHealth
+		unmarshalErr := json.Unmarshal(rawEvent, &event)
 		if unmarshalErr != nil {
-			return []LeaderboardScore{}, unmarshalErr
+			return unmarshalErr
 		}
 
-		if partialEvent.Name == Event_Game_Game_DiscoveredHealth {
-			var event Game_Game_DiscoveredHealth
-			unmarshalErr := json.Unmarshal(partialEvent.Event, &event)
-			if unmarshalErr != nil {
-				return []LeaderboardScore{}, unmarshalErr
-			}
+		adventurerRaw := big.NewInt(0)
+		adventurerRaw.SetString(event.Discovery.AdventurerState.AdventurerId, 0)
+		adventurer = adventurerRaw.String()
 
-			adventurerRaw := big.NewInt(0)
-			adventurerRaw.SetString(event.Discovery.AdventurerState.AdventurerId, 0)
-			adventurer := adventurerRaw.String()
+		owner := event.Discovery.AdventurerState.Owner
+		state.ActiveOwners[adventurer] = owner
+	} else if eventName == Event_Game_Game_DiscoveredGold {
+		var event Game_Game_DiscoveredGold
+		unmarshalErr := json.Unmarshal(rawEvent, &event)
+		if unmarshalErr != nil {
+			return unmarshalErr
+		}
 
-			owner := event.Discovery.AdventurerState.Owner
-			activeOwners[adventurer] = owner
+		adventurerRaw := big.NewInt(0)
+		adventurerRaw.SetString(event.Discovery.AdventurerState.AdventurerId, 0)
+		adventurer = adventurerRaw.String()
 
-			score := subscores[Event_Game_Game_DiscoveredHealth][adventurer]
-			subscores[Event_Game_Game_DiscoveredHealth][adventurer] = score + 1
-		} else if partialEvent.Name == Event_Game_Game_DiscoveredGold {
-			var event Game_Game_DiscoveredGold
-			unmarshalErr := json.Unmarshal(partialEvent.Event, &event)
-			if unmarshalErr != nil {
-				return []LeaderboardScore{}, unmarshalErr
-			}
+		owner := event.Discovery.AdventurerState.Owner
+		state.ActiveOwners[adventurer] = owner
+	} else if eventName == Event_Game_Game_DiscoveredBeast {
+		var event Game_Game_DiscoveredBeast
+		unmarshalErr := json.Unmarshal(rawEvent, &event)
+		if unmarshalErr != nil {
+			return unmarshalErr
+		}
 
-			adventurerRaw := big.NewInt(0)
-			adventurerRaw.SetString(event.Discovery.AdventurerState.AdventurerId, 0)
-			adventurer := adventurerRaw.String()
+		adventurerRaw := big.NewInt(0)
+		adventurerRaw.SetString(event.AdventurerState.AdventurerId, 0)
+		adventurer = adventurerRaw.String()
 
-			owner := event.Discovery.AdventurerState.Owner
-			activeOwners[adventurer] = owner
+		owner := event.AdventurerState.Owner
 
-			score := subscores[Event_Game_Game_DiscoveredGold][adventurer]
-			subscores[Event_Game_Game_DiscoveredGold][adventurer] = score + 1
-		} else if partialEvent.Name == Event_Game_Game_DiscoveredBeast {
-			var event Game_Game_DiscoveredBeast
-			unmarshalErr := json.Unmarshal(partialEvent.Event, &event)
-			if unmarshalErr != nil {
-				return []LeaderboardScore{}, unmarshalErr
-			}
+		state.ActiveOwners[adventurer] = owner
+	} else if eventName == Event_Game_Game_DodgedObstacle {
+		var event Game_Game_DodgedObstacle
+		unmarshalErr := json.Unmarshal(rawEvent, &event)
+		if unmarshalErr != nil {
+			return unmarshalErr
+		}
 
-			adventurerRaw := big.NewInt(0)
-			adventurerRaw.SetString(event.AdventurerState.AdventurerId, 0)
-			adventurer := adventurerRaw.String()
+		adventurerRaw := big.NewInt(0)
+		adventurerRaw.SetString(event.ObstacleEvent.AdventurerState.AdventurerId, 0)
+		adventurer = adventurerRaw.String()
 
-			owner := event.AdventurerState.Owner
+		owner := event.ObstacleEvent.AdventurerState.Owner
+		state.ActiveOwners[adventurer] = owner
+	} else if eventName == Event_Game_Game_HitByObstacle {
+		var event Game_Game_HitByObstacle
+		unmarshalErr := json.Unmarshal(rawEvent, &event)
+		if unmarshalErr != nil {
+			return unmarshalErr
+		}
 
-			activeOwners[adventurer] = owner
+		adventurerRaw := big.NewInt(0)
+		adventurerRaw.SetString(event.ObstacleEvent.AdventurerState.AdventurerId, 0)
+		adventurer = adventurerRaw.String()
 
-			score := subscores[Event_Game_Game_DiscoveredBeast][adventurer]
-			subscores[Event_Game_Game_DiscoveredBeast][adventurer] = score + 1
-		} else if partialEvent.Name == Event_Game_Game_DodgedObstacle {
-			var event Game_Game_DodgedObstacle
-			unmarshalErr := json.Unmarshal(partialEvent.Event, &event)
-			if unmarshalErr != nil {
-				return []LeaderboardScore{}, unmarshalErr
-			}
+		owner := event.ObstacleEvent.AdventurerState.Owner
+		state.ActiveOwners[adventurer] = owner
+	} else if eventName == Event_Game_Game_AmbushedByBeast {
+		var event Game_Game_AmbushedByBeast
+		unmarshalErr := json.Unmarshal(rawEvent, &event)
+		if unmarshalErr != nil {
+			return unmarshalErr
+		}
 
-			adventurerRaw := big.NewInt(0)
-			adventurerRaw.SetString(event.ObstacleEvent.AdventurerState.AdventurerId, 0)
-			adventurer := adventurerRaw.String()
+		adventurerRaw := big.NewInt(0)
+		adventurerRaw.SetString(event.AdventurerState.AdventurerId, 0)
+		adventurer = adventurerRaw.String()
 
-			owner := event.ObstacleEvent.AdventurerState.Owner
-			activeOwners[adventurer] = owner
+		owner := event.AdventurerState.Owner
+		state.ActiveOwners[adventurer] = owner
+	} else if eventName == Event_Game_Game_SlayedBeast {
+		var event Game_Game_SlayedBeast
+		unmarshalErr := json.Unmarshal(rawEvent, &event)
+		if unmarshalErr != nil {
+			return unmarshalErr
+		}
 
-			score := subscores[Event_Game_Game_DodgedObstacle][adventurer]
-			subscores[Event_Game_Game_DodgedObstacle][adventurer] = score + 1
-		} else if partialEvent.Name == Event_Game_Game_HitByObstacle {
-			var event Game_Game_HitByObstacle
-			unmarshalErr := json.Unmarshal(partialEvent.Event, &event)
-			if unmarshalErr != nil {
-				return []LeaderboardScore{}, unmarshalErr
-			}
+		adventurerRaw := big.NewInt(0)
+		adventurerRaw.SetString(event.AdventurerState.AdventurerId, 0)
+		adventurer = adventurerRaw.String()
 
-			adventurerRaw := big.NewInt(0)
-			adventurerRaw.SetString(event.ObstacleEvent.AdventurerState.AdventurerId, 0)
-			adventurer := adventurerRaw.String()
+		owner := event.AdventurerState.Owner
+		state.ActiveOwners[adventurer] = owner
 
-			owner := event.ObstacleEvent.AdventurerState.Owner
-			activeOwners[adventurer] = owner
+		maxLevel := state.Subscores["MaxLevelOfBeastSlayed"][adventurer]
+		if int(event.BeastSpecs.Level) > maxLevel {
+			state.addSubscore("MaxLevelOfBeastSlayed", adventurer, int(event.BeastSpecs.Level)-maxLevel)
+		}
+	} else if eventName == Event_Game_Game_FleeFailed {
+		var event Game_Game_FleeFailed
+		unmarshalErr := json.Unmarshal(rawEvent, &event)
+		if unmarshalErr != nil {
+			return unmarshalErr
+		}
 
-			score := subscores[Event_Game_Game_HitByObstacle][adventurer]
-			subscores[Event_Game_Game_HitByObstacle][adventurer] = score + 1
-		} else if partialEvent.Name == Event_Game_Game_AmbushedByBeast {
-			var event Game_Game_AmbushedByBeast
-			unmarshalErr := json.Unmarshal(partialEvent.Event, &event)
-			if unmarshalErr != nil {
-				return []LeaderboardScore{}, unmarshalErr
-			}
+		adventurerRaw := big.NewInt(0)
+		adventurerRaw.SetString(event.FleeEvent.AdventurerState.AdventurerId, 0)
+		adventurer = adventurerRaw.String()
 
-			adventurerRaw := big.NewInt(0)
-			adventurerRaw.SetString(event.AdventurerState.AdventurerId, 0)
-			adventurer := adventurerRaw.String()
+		owner := event.FleeEvent.AdventurerState.Owner
+		state.ActiveOwners[adventurer] = owner
+	} else if eventName == Event_Game_Game_FleeSucceeded {
+		var event Game_Game_FleeSucceeded
+		unmarshalErr := json.Unmarshal(rawEvent, &event)
+		if unmarshalErr != nil {
+			return unmarshalErr
+		}
 
-			owner := event.AdventurerState.Owner
-			activeOwners[adventurer] = owner
+		adventurerRaw := big.NewInt(0)
+		adventurerRaw.SetString(event.FleeEvent.AdventurerState.AdventurerId, 0)
+		adventurer = adventurerRaw.String()
 
-			score := subscores[Event_Game_Game_AmbushedByBeast][adventurer]
-			subscores[Event_Game_Game_AmbushedByBeast][adventurer] = score + 1
-		} else if partialEvent.Name == Event_Game_Game_SlayedBeast {
-			var event Game_Game_SlayedBeast
-			unmarshalErr := json.Unmarshal(partialEvent.Event, &event)
-			if unmarshalErr != nil {
-				return []LeaderboardScore{}, unmarshalErr
+		owner := event.FleeEvent.AdventurerState.Owner
+		state.ActiveOwners[adventurer] = owner
+	} else if eventName == Event_Game_Game_PurchasedItems {
+		var event Game_Game_PurchasedItems
+		unmarshalErr := json.Unmarshal(rawEvent, &event)
+		if unmarshalErr != nil {
+			return unmarshalErr
+		}
+
+		adventurerRaw := big.NewInt(0)
+		adventurerRaw.SetString(event.AdventurerStateWithBag.AdventurerState.AdventurerId, 0)
+		adventurer = adventurerRaw.String()
+
+		owner := event.AdventurerStateWithBag.AdventurerState.Owner
+		state.ActiveOwners[adventurer] = owner
+
+		units = len(event.Purchases)
+	} else if eventName == Event_Game_Game_PurchasedPotions {
+		var event Game_Game_PurchasedPotions
+		unmarshalErr := json.Unmarshal(rawEvent, &event)
+		if unmarshalErr != nil {
+			return unmarshalErr
+		}
+
+		adventurerRaw := big.NewInt(0)
+		adventurerRaw.SetString(event.AdventurerState.AdventurerId, 0)
+		adventurer = adventurerRaw.String()
+
+		owner := event.AdventurerState.Owner
+		state.ActiveOwners[adventurer] = owner
+
+		units = int(event.Quantity)
+	} else if eventName == Event_Game_Game_AdventurerLeveledUp {
+		var event Game_Game_AdventurerLeveledUp
+		unmarshalErr := json.Unmarshal(rawEvent, &event)
+		if unmarshalErr != nil {
+			return unmarshalErr
+		}
+
+		adventurerRaw := big.NewInt(0)
+		adventurerRaw.SetString(event.AdventurerState.AdventurerId, 0)
+		adventurer = adventurerRaw.String()
+
+		owner := event.AdventurerState.Owner
+		state.ActiveOwners[adventurer] = owner
+
+		units = int(event.NewLevel - event.PreviousLevel)
+	} else if eventName == Event_Game_Game_AdventurerUpgraded {
+		var event Game_Game_AdventurerUpgraded
+		unmarshalErr := json.Unmarshal(rawEvent, &event)
+		if unmarshalErr != nil {
+			return unmarshalErr
+		}
+
+		adventurerRaw := big.NewInt(0)
+		adventurerRaw.SetString(event.AdventurerStateWithBag.AdventurerState.AdventurerId, 0)
+		adventurer = adventurerRaw.String()
+
+		owner := event.AdventurerStateWithBag.AdventurerState.Owner
+		state.ActiveOwners[adventurer] = owner
+
+		units = int(event.CharismaIncrease + event.DexterityIncrease + event.IntelligenceIncrease + event.StrengthIncrease + event.VitalityIncrease + event.WisdomIncrease)
+	} else if eventName == Event_Game_Game_IdleDeathPenalty {
+		var event Game_Game_IdleDeathPenalty
+		unmarshalErr := json.Unmarshal(rawEvent, &event)
+		if unmarshalErr != nil {
+			return unmarshalErr
+		}
+
+		adventurerRaw := big.NewInt(0)
+		adventurerRaw.SetString(event.AdventurerState.AdventurerId, 0)
+		adventurer = adventurerRaw.String()
+
+		owner := event.AdventurerState.Owner
+		state.ActiveOwners[adventurer] = owner
+	} else if eventName == Event_Game_Game_AdventurerDied {
+		var event Game_Game_AdventurerDied
+		unmarshalErr := json.Unmarshal(rawEvent, &event)
+		if unmarshalErr != nil {
+			return unmarshalErr
+		}
+
+		adventurerRaw := big.NewInt(0)
+		adventurerRaw.SetString(event.AdventurerState.AdventurerId, 0)
+		adventurer = adventurerRaw.String()
+
+		owner := event.AdventurerState.Owner
+		state.ActiveOwners[adventurer] = owner
+	} else if eventName == Event_Game_Game_StartGame {
+		var event Game_Game_StartGame
+		unmarshalErr := json.Unmarshal(rawEvent, &event)
+		if unmarshalErr != nil {
+			return unmarshalErr
+		}
+
+		adventurerRaw := big.NewInt(0)
+		adventurerRaw.SetString(event.AdventurerState.AdventurerId, 0)
+		adventurer = adventurerRaw.String()
+
+		owner := event.AdventurerState.Owner
+		state.ActiveOwners[adventurer] = owner
+
+		state.Names[adventurer] = AdventurerDisplayName(event.AdventurerMeta.Name, adventurer)
+	} else {
+		// Other events count once towards the subscore of the adventurer they concern, if any.
+		var owner string
+		adventurer, owner = EventSubjects(rawEvent)
+		if adventurer == "" {
+			return nil
+		}
+		if owner != "" {
+			state.ActiveOwners[adventurer] = owner
+		}
+	}
+
+	adventurerState, hasState := EventAdventurerState(rawEvent)
+	if hasState && adventurerState.Adventurer.Xp > state.XP[adventurer] {
+		state.XP[adventurer] = adventurerState.Adventurer.Xp
+	}
+
+	if _, scored := state.Scoring.Events[eventName]; !scored {
+		return nil
+	}
+
+	eventScore, scoreErr := state.Scoring.EventScore(eventName, rawEvent)
+	if scoreErr != nil {
+		return scoreErr
+	}
+
+	state.addSubscore(eventName, adventurer, units)
+	if _, ok := state.Points[eventName]; !ok {
+		state.Points[eventName] = make(map[string]int)
+	}
+	state.Points[eventName][adventurer] += units * eventScore
+	if units*eventScore != 0 {
+		state.ScoreBlocks[adventurer] = blockNumber
+	}
+
+	return nil
+}
+
+// Builds the leaderboard from the state, ranking either adventurers or their owners (see AGGREGATE_BY_*).
+func (state *LootSurvivorLeaderboardState) Leaderboard(aggregateBy string) ([]LeaderboardScore, error) {
+	switch aggregateBy {
+	case AGGREGATE_BY_ADVENTURER:
+		return state.AdventurerLeaderboard(), nil
+	case AGGREGATE_BY_OWNER:
+		return state.OwnerLeaderboard(), nil
+	default:
+		return []LeaderboardScore{}, fmt.Errorf("%w: %s", ErrInvalidAggregation, aggregateBy)
+	}
+}
+
+// Returns the address under which an adventurer appears on leaderboards: their name (a Cairo short string,
+// see ShortStringName) followed by their ID, as in "loaf - 42", or just their ID if they have no name.
+func AdventurerDisplayName(name *big.Int, adventurer string) string {
+	decodedName := ShortStringName(name)
+	if decodedName == "" {
+		return adventurer
+	}
+	return fmt.Sprintf("%s - %s", decodedName, adventurer)
+}
+
+// Builds the leaderboard of adventurers from the state.
+func (state *LootSurvivorLeaderboardState) AdventurerLeaderboard() []LeaderboardScore {
+	scores := make(map[string]int)
+	pointsData := make(map[string]map[string]interface{})
+	for scoreComponent, data := range state.Subscores {
+		for adventurer, subscore := range data {
+			scores[adventurer] += state.Points[scoreComponent][adventurer]
+			if _, ok := pointsData[adventurer]; !ok {
+				pointsData[adventurer] = make(map[string]interface{})
 			}
+			clean_score_component := strings.TrimPrefix(scoreComponent, "game::Game::")
+			pointsData[adventurer][clean_score_component] = subscore
+		}
+	}
+	leaderboard := make([]LeaderboardScore, len(scores))
+	i := 0
+	for adventurer, score := range scores {
+		leaderboard[i] = LeaderboardScore{
+			Address:    state.Names[adventurer],
+			Score:      int(score),
+			PointsData: pointsData[adventurer],
+			TieBreakers: TieBreakers{
+				ScoreBlock: state.ScoreBlocks[adventurer],
+				XP:         state.XP[adventurer],
+				ID:         ParseTieBreakerID(adventurer),
+			},
+		}
+		i++
+	}
 
-			adventurerRaw := big.NewInt(0)
-			adventurerRaw.SetString(event.AdventurerState.AdventurerId, 0)
-			adventurer := adventurerRaw.String()
+	return leaderboard
+}
+
+// Builds the leaderboard of owners from the state. Each adventurer's subscores are added to those of its
+// active owner (except for MaxLevelOfBeastSlayed, of which the owner gets the maximum over their
+// adventurers), and an owner's score is the sum of their adventurers' scores. The "points_data" of each
+// owner also reports their number of adventurers and the best score of any one of them. Adventurers whose
+// owner is not known are left out. For tie-breaking, an owner reached their score when the score of the
+// last of their adventurers last changed, and their XP is the total XP of their adventurers.
+func (state *LootSurvivorLeaderboardState) OwnerLeaderboard() []LeaderboardScore {
+	adventurerScores := make(map[string]int)
+	for scoreComponent, data := range state.Subscores {
+		for adventurer := range data {
+			adventurerScores[adventurer] += state.Points[scoreComponent][adventurer]
+		}
+	}
 
-			owner := event.AdventurerState.Owner
-			activeOwners[adventurer] = owner
+	scores := make(map[string]int)
+	pointsData := make(map[string]map[string]interface{})
+	tieBreakers := make(map[string]TieBreakers)
+	for adventurer, adventurerScore := range adventurerScores {
+		owner, ok := state.ActiveOwners[adventurer]
+		if !ok || owner == "" {
+			continue
+		}
 
-			score := subscores[Event_Game_Game_SlayedBeast][adventurer]
-			subscores[Event_Game_Game_SlayedBeast][adventurer] = score + 1
+		ownerTieBreakers := tieBreakers[owner]
+		if state.ScoreBlocks[adventurer] > ownerTieBreakers.ScoreBlock {
+			ownerTieBreakers.ScoreBlock = state.ScoreBlocks[adventurer]
+		}
+		ownerTieBreakers.XP += state.XP[adventurer]
+		tieBreakers[owner] = ownerTieBreakers
 
-			maxLevel := subscores["MaxLevelOfBeastSlayed"][adventurer]
-			if int(event.BeastSpecs.Level) > maxLevel {
-				subscores["MaxLevelOfBeastSlayed"][adventurer] = int(event.BeastSpecs.Level)
+		if _, ok := pointsData[owner]; !ok {
+			pointsData[owner] = map[string]interface{}{
+				"Adventurers":         0,
+				"BestAdventurerScore": adventurerScore,
 			}
-		} else if partialEvent.Name == Event_Game_Game_FleeFailed {
-			var event Game_Game_FleeFailed
-			unmarshalErr := json.Unmarshal(partialEvent.Event, &event)
-			if unmarshalErr != nil {
-				return []LeaderboardScore{}, unmarshalErr
+		}
+		scores[owner] += adventurerScore
+		pointsData[owner]["Adventurers"] = pointsData[owner]["Adventurers"].(int) + 1
+		if adventurerScore > pointsData[owner]["BestAdventurerScore"].(int) {
+			pointsData[owner]["BestAdventurerScore"] = adventurerScore
+		}
+
+		for scoreComponent, data := range state.Subscores {
+			subscore, ok := data[adventurer]
+			if !ok {
+				continue
+			}
+			clean_score_component := strings.TrimPrefix(scoreComponent, "game::Game::")
+			current, _ := pointsData[owner][clean_score_component].(int)
+			if scoreComponent == "MaxLevelOfBeastSlayed" {
+				if subscore > current {
+					pointsData[owner][clean_score_component] = subscore
+				}
+			} else {
+				pointsData[owner][clean_score_component] = current + subscore
 			}
+		}
+	}
 
-			adventurerRaw := big.NewInt(0)
-			adventurerRaw.SetString(event.FleeEvent.AdventurerState.AdventurerId, 0)
-			adventurer := adventurerRaw.String()
+	leaderboard := make([]LeaderboardScore, len(scores))
+	i := 0
+	for owner, score := range scores {
+		ownerTieBreakers := tieBreakers[owner]
+		ownerTieBreakers.ID = ParseTieBreakerID(owner)
+		leaderboard[i] = LeaderboardScore{
+			Address:     owner,
+			Score:       score,
+			PointsData:  pointsData[owner],
+			TieBreakers: ownerTieBreakers,
+		}
+		i++
+	}
 
-			owner := event.FleeEvent.AdventurerState.Owner
-			activeOwners[adventurer] = owner
+	return leaderboard
+}
 
-			score := subscores[Event_Game_Game_FleeFailed][adventurer]
-			subscores[Event_Game_Game_FleeFailed][adventurer] = score + 1
-		} else if partialEvent.Name == Event_Game_Game_FleeSucceeded {
-			var event Game_Game_FleeSucceeded
-			unmarshalErr := json.Unmarshal(partialEvent.Event, &event)
-			if unmarshalErr != nil {
-				return []LeaderboardScore{}, unmarshalErr
-			}
+func LootSurvivorLeaderboard(eventsFile io.Reader, scoring TotalLeaderboardScoring, aggregateBy string) ([]LeaderboardScore, error) {
+	state := NewLootSurvivorLeaderboardState(scoring)
 
-			adventurerRaw := big.NewInt(0)
-			adventurerRaw.SetString(event.FleeEvent.AdventurerState.AdventurerId, 0)
-			adventurer := adventurerRaw.String()
+	scanner := bufio.NewScanner(eventsFile)
+	for scanner.Scan() {
+		line := scanner.Text()
+		var partialEvent PartialCrawledEvent
+		unmarshalErr := json.Unmarshal([]byte(line), &partialEvent)
+		if unmarshalErr != nil {
+			return []LeaderboardScore{}, unmarshalErr
+		}
 
-			owner := event.FleeEvent.AdventurerState.Owner
-			activeOwners[adventurer] = owner
+		foldErr := state.FoldEvent(partialEvent.Name, partialEvent.Event, partialEvent.BlockNumber)
+		if foldErr != nil {
+			return []LeaderboardScore{}, foldErr
+		}
+	}
 
-			score := subscores[Event_Game_Game_FleeSucceeded][adventurer]
-			subscores[Event_Game_Game_FleeSucceeded][adventurer] = score + 1
-		} else if partialEvent.Name == Event_Game_Game_PurchasedItems {
-			var event Game_Game_PurchasedItems
-			unmarshalErr := json.Unmarshal(partialEvent.Event, &event)
-			if unmarshalErr != nil {
-				return []LeaderboardScore{}, unmarshalErr
+	scanErr := scanner.Err()
+	if scanErr != nil {
+		return []LeaderboardScore{}, scanErr
+	}
+
+	return state.Leaderboard(aggregateBy)
+}
+
+// Builds LootSurvivorLeaderboard incrementally. Events from blocks up to and including state.LastBlock are
+// skipped, as the state already includes them, and the remaining events are folded in. The events must
+// carry their block information (as written by "stark events"), with retracted events already removed.
+//
+// Events from the last reorgDepth blocks in the file could still be retracted by a chain reorganization,
+// and the events of the latest block in the file may be incomplete, as the crawler writes its events a
+// chunk at a time and chunks can end in the middle of a block. These events are only folded into a copy
+// of the state from which the leaderboard is built. All the other events are folded into the state itself,
+// and state.LastBlock is advanced past them, so that the state can be persisted and used for the next run.
+// The leaderboard is the same as the one that LootSurvivorLeaderboard builds from the full history of
+// events.
+func IncrementalLootSurvivorLeaderboard(state *LootSurvivorLeaderboardState, eventsFile io.Reader, reorgDepth uint64, aggregateBy string) ([]LeaderboardScore, error) {
+	var pending []PartialCrawledEvent
+	var latestBlock uint64
+
+	// Folds the pending events which can no longer be retracted (and whose blocks are complete) into the
+	// state.
+	foldConfirmed := func() error {
+		confirmed := 0
+		for _, event := range pending {
+			if event.BlockNumber+reorgDepth >= latestBlock {
+				break
 			}
+			foldErr := state.FoldEvent(event.Name, event.Event, event.BlockNumber)
+			if foldErr != nil {
+				return foldErr
+			}
+			confirmed++
+		}
+		pending = pending[confirmed:]
+		return nil
+	}
 
-			adventurerRaw := big.NewInt(0)
-			adventurerRaw.SetString(event.AdventurerStateWithBag.AdventurerState.AdventurerId, 0)
-			adventurer := adventurerRaw.String()
+	scanner := bufio.NewScanner(eventsFile)
+	for scanner.Scan() {
+		var event PartialCrawledEvent
+		unmarshalErr := json.Unmarshal(scanner.Bytes(), &event)
+		if unmarshalErr != nil {
+			return []LeaderboardScore{}, unmarshalErr
+		}
+		if event.BlockHash == nil {
+			return []LeaderboardScore{}, ErrEventWithoutPosition
+		}
+		if event.BlockNumber <= state.LastBlock {
+			continue
+		}
+
+		if event.BlockNumber > latestBlock {
+			latestBlock = event.BlockNumber
+		}
+		pending = append(pending, event)
+
+		foldErr := foldConfirmed()
+		if foldErr != nil {
+			return []LeaderboardScore{}, foldErr
+		}
+	}
+
+	scanErr := scanner.Err()
+	if scanErr != nil {
+		return []LeaderboardScore{}, scanErr
+	}
+
+	if latestBlock > reorgDepth+1 && latestBlock-reorgDepth-1 > state.LastBlock {
+		state.LastBlock = latestBlock - reorgDepth - 1
+	}
+
+	current := state.Copy()
+	for _, event := range pending {
+		foldErr := current.FoldEvent(event.Name, event.Event, event.BlockNumber)
+		if foldErr != nil {
+			return []LeaderboardScore{}, foldErr
+		}
+	}
+
+	return current.Leaderboard(aggregateBy)
+}
+
+// Loads the state of LootSurvivorLeaderboard from the given file. Returns a new, empty state with the
+// given scoring if the file does not exist. Returns an error if the state in the file was built with a
+// different scoring, as its points would not match.
+func LoadLootSurvivorLeaderboardState(stateFile string, scoring TotalLeaderboardScoring) (*LootSurvivorLeaderboardState, error) {
+	contents, readErr := os.ReadFile(stateFile)
+	if readErr != nil {
+		if errors.Is(readErr, os.ErrNotExist) {
+			return NewLootSurvivorLeaderboardState(scoring), nil
+		}
+		return nil, readErr
+	}
 
-			owner := event.AdventurerStateWithBag.AdventurerState.Owner
-			activeOwners[adventurer] = owner
+	state := NewLootSurvivorLeaderboardState(scoring)
+	unmarshalErr := json.Unmarshal(contents, state)
+	if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+
+	stateScoring, stateMarshalErr := json.Marshal(state.Scoring)
+	if stateMarshalErr != nil {
+		return nil, stateMarshalErr
+	}
+	expectedScoring, expectedMarshalErr := json.Marshal(scoring)
+	if expectedMarshalErr != nil {
+		return nil, expectedMarshalErr
+	}
+	if !bytes.Equal(stateScoring, expectedScoring) {
+		return nil, fmt.Errorf("leaderboard state in %s was built with a different scoring (remove it to rebuild the leaderboard with the new scoring)", stateFile)
+	}
+
+	return state, nil
+}
+
+// Saves the state of LootSurvivorLeaderboard to the given file, atomically (see WriteFileAtomically).
+func SaveLootSurvivorLeaderboardState(stateFile string, state *LootSurvivorLeaderboardState) error {
+	contents, marshalErr := json.Marshal(state)
+	if marshalErr != nil {
+		return marshalErr
+	}
+
+	return WriteFileAtomically(stateFile, contents)
+}
+
+// Multiplier applied to the level of a slain beast, by beast tier. Tier 1 beasts (raw value 1) are the
+// most dangerous in Loot Survivor, and tier 5 beasts (raw value 5) the least.
+var BeastSlayersTierMultipliers map[Combat_Constants_CombatEnums_Tier]int = map[Combat_Constants_CombatEnums_Tier]int{
+	1: 5,
+	2: 4,
+	3: 3,
+	4: 2,
+	5: 1,
+}
+
+// Names of the events that BeastSlayersLeaderboard uses.
+var BeastSlayersLeaderboardEvents []string = []string{Event_Game_Game_SlayedBeast, Event_Game_Game_StartGame}
+
+// Builds the Beast Slayers leaderboard. Each beast that an adventurer slays earns them its level multiplied
+// by the multiplier for its tier (see BeastSlayersTierMultipliers).
+func BeastSlayersLeaderboard(eventsFile io.Reader) ([]LeaderboardScore, error) {
+	scores := make(map[string]int)
+	slayed := make(map[string]int)
+	maxLevel := make(map[string]int)
+	slayedByTier := make(map[string]map[Combat_Constants_CombatEnums_Tier]int)
+	scoreBlocks := make(map[string]uint64)
+	xp := make(map[string]uint64)
+
+	names := make(map[string]string)
+	scanner := bufio.NewScanner(eventsFile)
+	for scanner.Scan() {
+		line := scanner.Text()
+		var partialEvent PartialCrawledEvent
+		unmarshalErr := json.Unmarshal([]byte(line), &partialEvent)
+		if unmarshalErr != nil {
+			return []LeaderboardScore{}, unmarshalErr
+		}
 
-			score := subscores[Event_Game_Game_PurchasedItems][adventurer]
-			subscores[Event_Game_Game_PurchasedItems][adventurer] = score + len(event.Purchases)
-		} else if partialEvent.Name == Event_Game_Game_PurchasedPotions {
-			var event Game_Game_PurchasedPotions
+		if partialEvent.Name == Event_Game_Game_SlayedBeast {
+			var event Game_Game_SlayedBeast
 			unmarshalErr := json.Unmarshal(partialEvent.Event, &event)
 			if unmarshalErr != nil {
 				return []LeaderboardScore{}, unmarshalErr
@@ -289,13 +735,22 @@ func LootSurvivorLeaderboard(eventsFile *os.File) ([]LeaderboardScore, error) {
 			adventurerRaw.SetString(event.AdventurerState.AdventurerId, 0)
 			adventurer := adventurerRaw.String()
 
-			owner := event.AdventurerState.Owner
-			activeOwners[adventurer] = owner
+			level := int(event.BeastSpecs.Level)
+			tier := event.BeastSpecs.Tier
 
-			score := subscores[Event_Game_Game_PurchasedPotions][adventurer]
-			subscores[Event_Game_Game_PurchasedPotions][adventurer] = score + int(event.Quantity)
-		} else if partialEvent.Name == Event_Game_Game_AdventurerLeveledUp {
-			var event Game_Game_AdventurerLeveledUp
+			scores[adventurer] += level * BeastSlayersTierMultipliers[tier]
+			scoreBlocks[adventurer] = partialEvent.BlockNumber
+			xp[adventurer] = event.AdventurerState.Adventurer.Xp
+			slayed[adventurer]++
+			if level > maxLevel[adventurer] {
+				maxLevel[adventurer] = level
+			}
+			if _, ok := slayedByTier[adventurer]; !ok {
+				slayedByTier[adventurer] = make(map[Combat_Constants_CombatEnums_Tier]int)
+			}
+			slayedByTier[adventurer][tier]++
+		} else if partialEvent.Name == Event_Game_Game_StartGame {
+			var event Game_Game_StartGame
 			unmarshalErr := json.Unmarshal(partialEvent.Event, &event)
 			if unmarshalErr != nil {
 				return []LeaderboardScore{}, unmarshalErr
@@ -305,59 +760,93 @@ func LootSurvivorLeaderboard(eventsFile *os.File) ([]LeaderboardScore, error) {
 			adventurerRaw.SetString(event.AdventurerState.AdventurerId, 0)
 			adventurer := adventurerRaw.String()
 
-			owner := event.AdventurerState.Owner
-			activeOwners[adventurer] = owner
+			names[adventurer] = AdventurerDisplayName(event.AdventurerMeta.Name, adventurer)
+		}
+	}
 
-			score := subscores[Event_Game_Game_AdventurerLeveledUp][adventurer]
-			subscores[Event_Game_Game_AdventurerLeveledUp][adventurer] = score + int(event.NewLevel-event.PreviousLevel)
-		} else if partialEvent.Name == Event_Game_Game_AdventurerUpgraded {
-			var event Game_Game_AdventurerUpgraded
-			unmarshalErr := json.Unmarshal(partialEvent.Event, &event)
-			if unmarshalErr != nil {
-				return []LeaderboardScore{}, unmarshalErr
-			}
+	scanErr := scanner.Err()
+	if scanErr != nil {
+		return []LeaderboardScore{}, scanErr
+	}
 
-			adventurerRaw := big.NewInt(0)
-			adventurerRaw.SetString(event.AdventurerStateWithBag.AdventurerState.AdventurerId, 0)
-			adventurer := adventurerRaw.String()
+	leaderboard := make([]LeaderboardScore, len(scores))
+	i := 0
+	for adventurer, score := range scores {
+		pointsData := map[string]interface{}{
+			"SlayedBeast":           slayed[adventurer],
+			"MaxLevelOfBeastSlayed": maxLevel[adventurer],
+		}
+		for tier, count := range slayedByTier[adventurer] {
+			pointsData[fmt.Sprintf("SlayedBeastOfTier%d", tier)] = count
+		}
+		leaderboard[i] = LeaderboardScore{
+			Address:    names[adventurer],
+			Score:      score,
+			PointsData: pointsData,
+			TieBreakers: TieBreakers{
+				ScoreBlock: scoreBlocks[adventurer],
+				XP:         xp[adventurer],
+				ID:         ParseTieBreakerID(adventurer),
+			},
+		}
+		i++
+	}
 
-			owner := event.AdventurerStateWithBag.AdventurerState.Owner
-			activeOwners[adventurer] = owner
+	return leaderboard, nil
+}
+
+// Names of the events that ArtfulDodgersLeaderboard uses.
+var ArtfulDodgersLeaderboardEvents []string = []string{Event_Game_Game_DodgedObstacle, Event_Game_Game_HitByObstacle, Event_Game_Game_StartGame}
+
+// Builds the Artful Dodgers leaderboard. An adventurer's score is the number of obstacles they dodged less
+// the number of obstacles that hit them.
+func ArtfulDodgersLeaderboard(eventsFile io.Reader) ([]LeaderboardScore, error) {
+	dodged := make(map[string]int)
+	hit := make(map[string]int)
+	encountered := make(map[string]int)
+	scoreBlocks := make(map[string]uint64)
+	xp := make(map[string]uint64)
+
+	names := make(map[string]string)
+	scanner := bufio.NewScanner(eventsFile)
+	for scanner.Scan() {
+		line := scanner.Text()
+		var partialEvent PartialCrawledEvent
+		unmarshalErr := json.Unmarshal([]byte(line), &partialEvent)
+		if unmarshalErr != nil {
+			return []LeaderboardScore{}, unmarshalErr
+		}
 
-			score := subscores[Event_Game_Game_AdventurerUpgraded][adventurer]
-			subscores[Event_Game_Game_AdventurerUpgraded][adventurer] = score + int(event.CharismaIncrease+event.DexterityIncrease+event.IntelligenceIncrease+event.StrengthIncrease+event.VitalityIncrease+event.WisdomIncrease)
-		} else if partialEvent.Name == Event_Game_Game_IdleDeathPenalty {
-			var event Game_Game_IdleDeathPenalty
+		if partialEvent.Name == Event_Game_Game_DodgedObstacle {
+			var event Game_Game_DodgedObstacle
 			unmarshalErr := json.Unmarshal(partialEvent.Event, &event)
 			if unmarshalErr != nil {
 				return []LeaderboardScore{}, unmarshalErr
 			}
 
 			adventurerRaw := big.NewInt(0)
-			adventurerRaw.SetString(event.AdventurerState.AdventurerId, 0)
+			adventurerRaw.SetString(event.ObstacleEvent.AdventurerState.AdventurerId, 0)
 			adventurer := adventurerRaw.String()
 
-			owner := event.AdventurerState.Owner
-			activeOwners[adventurer] = owner
-
-			score := subscores[Event_Game_Game_IdleDeathPenalty][adventurer]
-			subscores[Event_Game_Game_IdleDeathPenalty][adventurer] = score + 1
-		} else if partialEvent.Name == Event_Game_Game_AdventurerDied {
-			var event Game_Game_AdventurerDied
+			dodged[adventurer]++
+			encountered[adventurer]++
+			scoreBlocks[adventurer] = partialEvent.BlockNumber
+			xp[adventurer] = event.ObstacleEvent.AdventurerState.Adventurer.Xp
+		} else if partialEvent.Name == Event_Game_Game_HitByObstacle {
+			var event Game_Game_HitByObstacle
 			unmarshalErr := json.Unmarshal(partialEvent.Event, &event)
 			if unmarshalErr != nil {
 				return []LeaderboardScore{}, unmarshalErr
 			}
 
 			adventurerRaw := big.NewInt(0)
-			adventurerRaw.SetString(event.AdventurerState.AdventurerId, 0)
+			adventurerRaw.SetString(event.ObstacleEvent.AdventurerState.AdventurerId, 0)
 			adventurer := adventurerRaw.String()
 
-			owner := event.AdventurerState.Owner
-			activeOwners[adventurer] = owner
-
-			score := subscores[Event_Game_Game_AdventurerDied][adventurer]
-			subscores[Event_Game_Game_AdventurerDied][adventurer] = score + 1
+			hit[adventurer]++
+			encountered[adventurer]++
+			scoreBlocks[adventurer] = partialEvent.BlockNumber
+			xp[adventurer] = event.ObstacleEvent.AdventurerState.Adventurer.Xp
 		} else if partialEvent.Name == Event_Game_Game_StartGame {
 			var event Game_Game_StartGame
 			unmarshalErr := json.Unmarshal(partialEvent.Event, &event)
@@ -369,32 +858,34 @@ func LootSurvivorLeaderboard(eventsFile *os.File) ([]LeaderboardScore, error) {
 			adventurerRaw.SetString(event.AdventurerState.AdventurerId, 0)
 			adventurer := adventurerRaw.String()
 
-			nameRaw := event.AdventurerMeta.Name
-			name := string(nameRaw.Bytes())
-
-			names[adventurer] = fmt.Sprintf("%s - %s", name, adventurer)
+			names[adventurer] = AdventurerDisplayName(event.AdventurerMeta.Name, adventurer)
 		}
 	}
 
-	scores := make(map[string]int)
-	pointsData := make(map[string]map[string]interface{})
-	for scoreComponent, data := range subscores {
-		for adventurer, subscore := range data {
-			scores[adventurer] += TotalLeaderboardEventScores[scoreComponent] * subscore
-			if _, ok := pointsData[adventurer]; !ok {
-				pointsData[adventurer] = make(map[string]interface{})
-			}
-			clean_score_component := strings.TrimPrefix(scoreComponent, "game::Game::")
-			pointsData[adventurer][clean_score_component] = subscore
-		}
+	scanErr := scanner.Err()
+	if scanErr != nil {
+		return []LeaderboardScore{}, scanErr
 	}
-	leaderboard := make([]LeaderboardScore, len(scores))
+
+	leaderboard := make([]LeaderboardScore, len(encountered))
 	i := 0
-	for adventurer, score := range scores {
+	for adventurer, encounteredCount := range encountered {
+		dodgedCount := dodged[adventurer]
+		hitCount := hit[adventurer]
 		leaderboard[i] = LeaderboardScore{
-			Address:    names[adventurer],
-			Score:      int(score),
-			PointsData: pointsData[adventurer],
+			Address: names[adventurer],
+			Score:   dodgedCount - hitCount,
+			PointsData: map[string]interface{}{
+				"DodgedObstacle": dodgedCount,
+				"HitByObstacle":  hitCount,
+				// Percentage of obstacles encountered that the adventurer dodged.
+				"DodgeRate": (100 * dodgedCount) / encounteredCount,
+			},
+			TieBreakers: TieBreakers{
+				ScoreBlock: scoreBlocks[adventurer],
+				XP:         xp[adventurer],
+				ID:         ParseTieBreakerID(adventurer),
+			},
 		}
 		i++
 	}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// Returns the lines that "stark events" writes for a short history of games: adventurers 1 and 2 belong to
+// 0xa and adventurer 3 to 0xb, and each of them earns points over the first 12 blocks.
+func gameEventLines(t *testing.T) [][]byte {
+	t.Helper()
+	adventurerState := func(adventurer, owner string, xp uint64) Game_Game_AdventurerState {
+		return Game_Game_AdventurerState{Owner: owner, AdventurerId: adventurer, Adventurer: Survivor_Adventurer_Adventurer{Xp: xp}}
+	}
+	loaf, _ := EncodeShortString("loaf")
+
+	events := []struct {
+		blockNumber uint64
+		name        string
+		event       interface{}
+	}{
+		{1, Event_Game_Game_StartGame, Game_Game_StartGame{AdventurerState: adventurerState("1", "0xa", 0), AdventurerMeta: Survivor_AdventurerMeta_AdventurerMetadata{Name: loaf}}},
+		{1, Event_Game_Game_StartGame, Game_Game_StartGame{AdventurerState: adventurerState("3", "0xb", 0)}},
+		{2, Event_Game_Game_DiscoveredBeast, Game_Game_DiscoveredBeast{AdventurerState: adventurerState("1", "0xa", 0)}},
+		{3, Event_Game_Game_SlayedBeast, Game_Game_SlayedBeast{AdventurerState: adventurerState("1", "0xa", 4), BeastSpecs: Combat_Combat_CombatSpec{Level: 3}}},
+		{3, Event_Game_Game_DiscoveredGold, Game_Game_DiscoveredGold{Discovery: Game_Game_Discovery{AdventurerState: adventurerState("3", "0xb", 2), Amount: 5}}},
+		{4, Event_Game_Game_StartGame, Game_Game_StartGame{AdventurerState: adventurerState("2", "0xa", 0)}},
+		{5, Event_Game_Game_AdventurerLeveledUp, Game_Game_AdventurerLeveledUp{AdventurerState: adventurerState("1", "0xa", 9), PreviousLevel: 2, NewLevel: 3}},
+		{6, Event_Game_Game_DiscoveredBeast, Game_Game_DiscoveredBeast{AdventurerState: adventurerState("2", "0xa", 0)}},
+		{7, Event_Game_Game_SlayedBeast, Game_Game_SlayedBeast{AdventurerState: adventurerState("2", "0xa", 5), BeastSpecs: Combat_Combat_CombatSpec{Level: 6}}},
+		{7, Event_Game_Game_SlayedBeast, Game_Game_SlayedBeast{AdventurerState: adventurerState("3", "0xb", 8), BeastSpecs: Combat_Combat_CombatSpec{Level: 2}}},
+		{9, Event_Game_Game_DiscoveredGold, Game_Game_DiscoveredGold{Discovery: Game_Game_Discovery{AdventurerState: adventurerState("1", "0xa", 12), Amount: 3}}},
+		{10, Event_Game_Game_SlayedBeast, Game_Game_SlayedBeast{AdventurerState: adventurerState("1", "0xa", 20), BeastSpecs: Combat_Combat_CombatSpec{Level: 1}}},
+		{11, Event_Game_Game_IdleDeathPenalty, Game_Game_IdleDeathPenalty{AdventurerState: adventurerState("3", "0xb", 8)}},
+		{12, Event_Game_Game_AdventurerDied, Game_Game_AdventurerDied{AdventurerState: adventurerState("2", "0xa", 5)}},
+	}
+
+	lines := make([][]byte, len(events))
+	for i, event := range events {
+		line, marshalErr := json.Marshal(CrawledEvent{
+			Name:            event.name,
+			Event:           event.event,
+			BlockNumber:     event.blockNumber,
+			BlockHash:       new(felt.Felt).SetUint64(event.blockNumber * 1000),
+			TransactionHash: new(felt.Felt).SetUint64(uint64(i)),
+			FromAddress:     new(felt.Felt).SetUint64(0xa),
+		})
+		if marshalErr != nil {
+			t.Fatalf("could not marshal event: %s", marshalErr.Error())
+		}
+		lines[i] = line
+	}
+	return lines
+}
+
+// Returns the lines for the events from blocks up to and including the given block.
+func linesUpToBlock(t *testing.T, lines [][]byte, blockNumber uint64) []byte {
+	t.Helper()
+	var result []byte
+	for _, line := range lines {
+		var event PartialCrawledEvent
+		if unmarshalErr := json.Unmarshal(line, &event); unmarshalErr != nil {
+			t.Fatal(unmarshalErr)
+		}
+		if event.BlockNumber <= blockNumber {
+			result = append(append(result, line...), '\n')
+		}
+	}
+	return result
+}
+
+func TestIncrementalLootSurvivorLeaderboardMatchesFullRecompute(t *testing.T) {
+	lines := gameEventLines(t)
+	scoring := DefaultTotalLeaderboardScoring()
+	tieBreakers := []string{TIE_BREAKER_EARLIEST, TIE_BREAKER_XP, TIE_BREAKER_ID}
+
+	for _, aggregateBy := range []string{AGGREGATE_BY_ADVENTURER, AGGREGATE_BY_OWNER} {
+		t.Run(aggregateBy, func(t *testing.T) {
+			stateFile := filepath.Join(t.TempDir(), "state.json")
+
+			// Each run sees the events file as it stood after the given block, and resumes from the state
+			// that the previous run saved.
+			for _, lastBlock := range []uint64{2, 3, 3, 7, 8, 12, 12} {
+				eventsFile := linesUpToBlock(t, lines, lastBlock)
+
+				expected, fullErr := LootSurvivorLeaderboard(bytes.NewReader(eventsFile), scoring, aggregateBy)
+				if fullErr != nil {
+					t.Fatalf("could not build leaderboard up to block %d: %s", lastBlock, fullErr.Error())
+				}
+
+				state, loadErr := LoadLootSurvivorLeaderboardState(stateFile, scoring)
+				if loadErr != nil {
+					t.Fatalf("could not load state: %s", loadErr.Error())
+				}
+				incremental, incrementalErr := IncrementalLootSurvivorLeaderboard(state, bytes.NewReader(eventsFile), 2, aggregateBy)
+				if incrementalErr != nil {
+					t.Fatalf("could not build leaderboard incrementally up to block %d: %s", lastBlock, incrementalErr.Error())
+				}
+				if saveErr := SaveLootSurvivorLeaderboardState(stateFile, state); saveErr != nil {
+					t.Fatalf("could not save state: %s", saveErr.Error())
+				}
+
+				RankLeaderboard(expected, tieBreakers)
+				RankLeaderboard(incremental, tieBreakers)
+				if len(expected) == 0 {
+					t.Fatalf("expected a non-empty leaderboard up to block %d", lastBlock)
+				}
+				if !reflect.DeepEqual(incremental, expected) {
+					t.Errorf("up to block %d, expected leaderboard %+v, got %+v", lastBlock, expected, incremental)
+				}
+			}
+		})
+	}
+}
+
+func TestIncrementalLootSurvivorLeaderboardBlockSplitAcrossRuns(t *testing.T) {
+	lines := gameEventLines(t)
+	scoring := DefaultTotalLeaderboardScoring()
+	state := NewLootSurvivorLeaderboardState(scoring)
+
+	// Block 7 holds two events, and the first run only sees the first of them, as if the crawl had
+	// written the chunk which ends in the middle of block 7 but not the next one.
+	var firstRun []byte
+	for _, line := range lines[:9] {
+		firstRun = append(append(firstRun, line...), '\n')
+	}
+	if _, firstErr := IncrementalLootSurvivorLeaderboard(state, bytes.NewReader(firstRun), 0, AGGREGATE_BY_ADVENTURER); firstErr != nil {
+		t.Fatalf("could not build leaderboard: %s", firstErr.Error())
+	}
+	if state.LastBlock >= 7 {
+		t.Fatalf("expected the state to leave out the partially written block 7, but it includes blocks up to %d", state.LastBlock)
+	}
+
+	allEvents := linesUpToBlock(t, lines, 12)
+	incremental, incrementalErr := IncrementalLootSurvivorLeaderboard(state, bytes.NewReader(allEvents), 0, AGGREGATE_BY_ADVENTURER)
+	if incrementalErr != nil {
+		t.Fatalf("could not build leaderboard incrementally: %s", incrementalErr.Error())
+	}
+	expected, fullErr := LootSurvivorLeaderboard(bytes.NewReader(allEvents), scoring, AGGREGATE_BY_ADVENTURER)
+	if fullErr != nil {
+		t.Fatalf("could not build leaderboard: %s", fullErr.Error())
+	}
+
+	RankLeaderboard(expected, nil)
+	RankLeaderboard(incremental, nil)
+	if !reflect.DeepEqual(incremental, expected) {
+		t.Errorf("expected leaderboard %+v, got %+v", expected, incremental)
+	}
+}
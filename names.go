@@ -0,0 +1,446 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Values of the Tier, ItemType and Slot enums of the LootSurvivor contract (see
+// EvaluateCombat_Constants_CombatEnums_Tier, EvaluateCombat_Constants_CombatEnums_Type and
+// EvaluateCombat_Constants_CombatEnums_Slot).
+var TIER_1 Combat_Constants_CombatEnums_Tier = 1
+var TIER_2 Combat_Constants_CombatEnums_Tier = 2
+var TIER_3 Combat_Constants_CombatEnums_Tier = 3
+var TIER_4 Combat_Constants_CombatEnums_Tier = 4
+var TIER_5 Combat_Constants_CombatEnums_Tier = 5
+
+var TYPE_MAGIC_OR_CLOTH Combat_Constants_CombatEnums_Type = 1
+var TYPE_BLADE_OR_HIDE Combat_Constants_CombatEnums_Type = 2
+var TYPE_BLUDGEON_OR_METAL Combat_Constants_CombatEnums_Type = 3
+var TYPE_NECKLACE Combat_Constants_CombatEnums_Type = 4
+var TYPE_RING Combat_Constants_CombatEnums_Type = 5
+
+var SLOT_WEAPON Combat_Constants_CombatEnums_Slot = 1
+var SLOT_CHEST Combat_Constants_CombatEnums_Slot = 2
+var SLOT_HEAD Combat_Constants_CombatEnums_Slot = 3
+var SLOT_WAIST Combat_Constants_CombatEnums_Slot = 4
+var SLOT_FOOT Combat_Constants_CombatEnums_Slot = 5
+var SLOT_HAND Combat_Constants_CombatEnums_Slot = 6
+var SLOT_NECK Combat_Constants_CombatEnums_Slot = 7
+var SLOT_RING Combat_Constants_CombatEnums_Slot = 8
+
+// ItemInfo is the static description of a Loot item, as the LootSurvivor contract defines it.
+type ItemInfo struct {
+	Id       uint64
+	Name     string
+	Tier     Combat_Constants_CombatEnums_Tier
+	ItemType Combat_Constants_CombatEnums_Type
+	Slot     Combat_Constants_CombatEnums_Slot
+}
+
+// Loot items, indexed by item ID. The entry at index 0 (no item) is empty.
+var ITEMS []ItemInfo = []ItemInfo{
+	{},
+	{Id: 1, Name: "Pendant", Tier: TIER_1, ItemType: TYPE_NECKLACE, Slot: SLOT_NECK},
+	{Id: 2, Name: "Necklace", Tier: TIER_1, ItemType: TYPE_NECKLACE, Slot: SLOT_NECK},
+	{Id: 3, Name: "Amulet", Tier: TIER_1, ItemType: TYPE_NECKLACE, Slot: SLOT_NECK},
+	{Id: 4, Name: "Silver Ring", Tier: TIER_2, ItemType: TYPE_RING, Slot: SLOT_RING},
+	{Id: 5, Name: "Bronze Ring", Tier: TIER_3, ItemType: TYPE_RING, Slot: SLOT_RING},
+	{Id: 6, Name: "Platinum Ring", Tier: TIER_1, ItemType: TYPE_RING, Slot: SLOT_RING},
+	{Id: 7, Name: "Titanium Ring", Tier: TIER_1, ItemType: TYPE_RING, Slot: SLOT_RING},
+	{Id: 8, Name: "Gold Ring", Tier: TIER_1, ItemType: TYPE_RING, Slot: SLOT_RING},
+	{Id: 9, Name: "Ghost Wand", Tier: TIER_1, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_WEAPON},
+	{Id: 10, Name: "Grave Wand", Tier: TIER_2, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_WEAPON},
+	{Id: 11, Name: "Bone Wand", Tier: TIER_3, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_WEAPON},
+	{Id: 12, Name: "Wand", Tier: TIER_5, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_WEAPON},
+	{Id: 13, Name: "Grimoire", Tier: TIER_1, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_WEAPON},
+	{Id: 14, Name: "Chronicle", Tier: TIER_2, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_WEAPON},
+	{Id: 15, Name: "Tome", Tier: TIER_3, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_WEAPON},
+	{Id: 16, Name: "Book", Tier: TIER_5, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_WEAPON},
+	{Id: 17, Name: "Divine Robe", Tier: TIER_1, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_CHEST},
+	{Id: 18, Name: "Silk Robe", Tier: TIER_2, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_CHEST},
+	{Id: 19, Name: "Linen Robe", Tier: TIER_3, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_CHEST},
+	{Id: 20, Name: "Robe", Tier: TIER_4, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_CHEST},
+	{Id: 21, Name: "Shirt", Tier: TIER_5, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_CHEST},
+	{Id: 22, Name: "Crown", Tier: TIER_1, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_HEAD},
+	{Id: 23, Name: "Divine Hood", Tier: TIER_2, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_HEAD},
+	{Id: 24, Name: "Silk Hood", Tier: TIER_3, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_HEAD},
+	{Id: 25, Name: "Linen Hood", Tier: TIER_4, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_HEAD},
+	{Id: 26, Name: "Hood", Tier: TIER_5, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_HEAD},
+	{Id: 27, Name: "Brightsilk Sash", Tier: TIER_1, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_WAIST},
+	{Id: 28, Name: "Silk Sash", Tier: TIER_2, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_WAIST},
+	{Id: 29, Name: "Wool Sash", Tier: TIER_3, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_WAIST},
+	{Id: 30, Name: "Linen Sash", Tier: TIER_4, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_WAIST},
+	{Id: 31, Name: "Sash", Tier: TIER_5, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_WAIST},
+	{Id: 32, Name: "Divine Slippers", Tier: TIER_1, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_FOOT},
+	{Id: 33, Name: "Silk Slippers", Tier: TIER_2, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_FOOT},
+	{Id: 34, Name: "Wool Shoes", Tier: TIER_3, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_FOOT},
+	{Id: 35, Name: "Linen Shoes", Tier: TIER_4, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_FOOT},
+	{Id: 36, Name: "Shoes", Tier: TIER_5, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_FOOT},
+	{Id: 37, Name: "Divine Gloves", Tier: TIER_1, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_HAND},
+	{Id: 38, Name: "Silk Gloves", Tier: TIER_2, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_HAND},
+	{Id: 39, Name: "Wool Gloves", Tier: TIER_3, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_HAND},
+	{Id: 40, Name: "Linen Gloves", Tier: TIER_4, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_HAND},
+	{Id: 41, Name: "Gloves", Tier: TIER_5, ItemType: TYPE_MAGIC_OR_CLOTH, Slot: SLOT_HAND},
+	{Id: 42, Name: "Katana", Tier: TIER_1, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_WEAPON},
+	{Id: 43, Name: "Falchion", Tier: TIER_2, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_WEAPON},
+	{Id: 44, Name: "Scimitar", Tier: TIER_3, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_WEAPON},
+	{Id: 45, Name: "Long Sword", Tier: TIER_4, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_WEAPON},
+	{Id: 46, Name: "Short Sword", Tier: TIER_5, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_WEAPON},
+	{Id: 47, Name: "Demon Husk", Tier: TIER_1, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_CHEST},
+	{Id: 48, Name: "Dragonskin Armor", Tier: TIER_2, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_CHEST},
+	{Id: 49, Name: "Studded Leather Armor", Tier: TIER_3, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_CHEST},
+	{Id: 50, Name: "Hard Leather Armor", Tier: TIER_4, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_CHEST},
+	{Id: 51, Name: "Leather Armor", Tier: TIER_5, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_CHEST},
+	{Id: 52, Name: "Demon Crown", Tier: TIER_1, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_HEAD},
+	{Id: 53, Name: "Dragon's Crown", Tier: TIER_2, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_HEAD},
+	{Id: 54, Name: "War Cap", Tier: TIER_3, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_HEAD},
+	{Id: 55, Name: "Leather Cap", Tier: TIER_4, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_HEAD},
+	{Id: 56, Name: "Cap", Tier: TIER_5, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_HEAD},
+	{Id: 57, Name: "Demonhide Belt", Tier: TIER_1, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_WAIST},
+	{Id: 58, Name: "Dragonskin Belt", Tier: TIER_2, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_WAIST},
+	{Id: 59, Name: "Studded Leather Belt", Tier: TIER_3, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_WAIST},
+	{Id: 60, Name: "Hard Leather Belt", Tier: TIER_4, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_WAIST},
+	{Id: 61, Name: "Leather Belt", Tier: TIER_5, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_WAIST},
+	{Id: 62, Name: "Demonhide Boots", Tier: TIER_1, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_FOOT},
+	{Id: 63, Name: "Dragonskin Boots", Tier: TIER_2, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_FOOT},
+	{Id: 64, Name: "Studded Leather Boots", Tier: TIER_3, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_FOOT},
+	{Id: 65, Name: "Hard Leather Boots", Tier: TIER_4, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_FOOT},
+	{Id: 66, Name: "Leather Boots", Tier: TIER_5, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_FOOT},
+	{Id: 67, Name: "Demon's Hands", Tier: TIER_1, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_HAND},
+	{Id: 68, Name: "Dragonskin Gloves", Tier: TIER_2, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_HAND},
+	{Id: 69, Name: "Studded Leather Gloves", Tier: TIER_3, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_HAND},
+	{Id: 70, Name: "Hard Leather Gloves", Tier: TIER_4, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_HAND},
+	{Id: 71, Name: "Leather Gloves", Tier: TIER_5, ItemType: TYPE_BLADE_OR_HIDE, Slot: SLOT_HAND},
+	{Id: 72, Name: "Warhammer", Tier: TIER_1, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_WEAPON},
+	{Id: 73, Name: "Quarterstaff", Tier: TIER_2, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_WEAPON},
+	{Id: 74, Name: "Maul", Tier: TIER_3, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_WEAPON},
+	{Id: 75, Name: "Mace", Tier: TIER_4, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_WEAPON},
+	{Id: 76, Name: "Club", Tier: TIER_5, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_WEAPON},
+	{Id: 77, Name: "Holy Chestplate", Tier: TIER_1, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_CHEST},
+	{Id: 78, Name: "Ornate Chestplate", Tier: TIER_2, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_CHEST},
+	{Id: 79, Name: "Plate Mail", Tier: TIER_3, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_CHEST},
+	{Id: 80, Name: "Chain Mail", Tier: TIER_4, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_CHEST},
+	{Id: 81, Name: "Ring Mail", Tier: TIER_5, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_CHEST},
+	{Id: 82, Name: "Ancient Helm", Tier: TIER_1, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_HEAD},
+	{Id: 83, Name: "Ornate Helm", Tier: TIER_2, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_HEAD},
+	{Id: 84, Name: "Great Helm", Tier: TIER_3, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_HEAD},
+	{Id: 85, Name: "Full Helm", Tier: TIER_4, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_HEAD},
+	{Id: 86, Name: "Helm", Tier: TIER_5, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_HEAD},
+	{Id: 87, Name: "Ornate Belt", Tier: TIER_1, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_WAIST},
+	{Id: 88, Name: "War Belt", Tier: TIER_2, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_WAIST},
+	{Id: 89, Name: "Plated Belt", Tier: TIER_3, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_WAIST},
+	{Id: 90, Name: "Mesh Belt", Tier: TIER_4, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_WAIST},
+	{Id: 91, Name: "Heavy Belt", Tier: TIER_5, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_WAIST},
+	{Id: 92, Name: "Holy Greaves", Tier: TIER_1, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_FOOT},
+	{Id: 93, Name: "Ornate Greaves", Tier: TIER_2, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_FOOT},
+	{Id: 94, Name: "Greaves", Tier: TIER_3, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_FOOT},
+	{Id: 95, Name: "Chain Boots", Tier: TIER_4, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_FOOT},
+	{Id: 96, Name: "Heavy Boots", Tier: TIER_5, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_FOOT},
+	{Id: 97, Name: "Holy Gauntlets", Tier: TIER_1, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_HAND},
+	{Id: 98, Name: "Ornate Gauntlets", Tier: TIER_2, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_HAND},
+	{Id: 99, Name: "Gauntlets", Tier: TIER_3, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_HAND},
+	{Id: 100, Name: "Chain Gloves", Tier: TIER_4, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_HAND},
+	{Id: 101, Name: "Heavy Gloves", Tier: TIER_5, ItemType: TYPE_BLUDGEON_OR_METAL, Slot: SLOT_HAND},
+}
+
+// Names of the beasts, indexed by beast ID. The beasts come in three groups of 25 (magical, hunters and
+// brutes), and each group in five tiers of 5 beasts, from T1 to T5 (see EncounterTierAndType).
+var BEAST_NAMES []string = []string{
+	"",
+	// Magical
+	"Warlock", "Typhon", "Jiangshi", "Anansi", "Basilisk",
+	"Gorgon", "Kitsune", "Lich", "Chimera", "Wendigo",
+	"Rakshasa", "Werewolf", "Banshee", "Draugr", "Vampire",
+	"Goblin", "Ghoul", "Wraith", "Sprite", "Kappa",
+	"Fairy", "Leprechaun", "Kelpie", "Pixie", "Gnome",
+	// Hunters
+	"Griffin", "Manticore", "Phoenix", "Dragon", "Minotaur",
+	"Qilin", "Ammit", "Nue", "Skinwalker", "Chupacabra",
+	"Weretiger", "Wyvern", "Roc", "Harpy", "Pegasus",
+	"Hippogriff", "Fenrir", "Jaguar", "Satori", "Dire Wolf",
+	"Bear", "Wolf", "Mantis", "Spider", "Rat",
+	// Brutes
+	"Kraken", "Colossus", "Balrog", "Leviathan", "Tarrasque",
+	"Titan", "Nephilim", "Behemoth", "Hydra", "Juggernaut",
+	"Oni", "Jotunn", "Ettin", "Cyclops", "Giant",
+	"Nemean Lion", "Berserker", "Yeti", "Golem", "Ent",
+	"Troll", "Bigfoot", "Ogre", "Orc", "Skeleton",
+}
+
+// Names of the obstacles, indexed by obstacle ID. Like the beasts, the obstacles come in three groups of
+// 25 (magical, sharp and crushing), each in five tiers of 5 obstacles. The names of magical obstacles 15
+// to 25 are not known yet, so they have empty entries and ObstacleName falls back to "obstacle <ID>".
+var OBSTACLE_NAMES []string = []string{
+	"",
+	// Magical
+	"Demonic Alter", "Vortex of Despair", "Eldritch Barrier", "Soul Trap", "Phantom Vortex",
+	"Ectoplasm Barrier", "Spectral Chains", "Infernal Pact", "Arcane Explosion", "Hypnotic Essence",
+	"Mischievous Sprites", "Soul Draining Statue", "Petrifying Gaze", "Wicked Hex", "",
+	"", "", "", "", "",
+	"", "", "", "", "",
+	// Sharp
+	"Pendulum Blades", "Icy Razor Winds", "Acidic Thorns", "Dragon's Breath", "Pendulum Scythe",
+	"Flame Jet", "Piercing Ice Darts", "Glass Sand Storm", "Poisoned Dart Wall", "Spinning Blade Wheel",
+	"Poison Dart", "Spiked Tumbleweed", "Thunderbolt", "Giant Bear Trap", "Steel Needle Rain",
+	"Spiked Pit", "Diamond Dust Storm", "Trapdoor Scorpion Pit", "Bladed Fan", "Bear Trap",
+	"Porcupine Quill", "Hidden Arrow", "Glass Shard", "Thorn Bush", "Jagged Rocks",
+	// Crushing
+	"Collapsing Ceiling", "Rockslide", "Flash Flood", "Clinging Roots", "Collapsing Cavern",
+	"Crushing Walls", "Smashing Pillars", "Rumbling Catacomb", "Whirling Cyclone", "Erupting Earth",
+	"Subterranean Tremor", "Falling Chandelier", "Collapsing Bridge", "Raging Sandstorm", "Avalanching Rocks",
+	"Tumbling Boulders", "Slamming Iron Gate", "Shifting Sandtrap", "Erupting Mud Geyser", "Crumbling Staircase",
+	"Swinging Logs", "Unstable Cliff", "Toppling Statue", "Tumbling Barrels", "Rolling Boulder",
+}
+
+// Names of the suffixes that an item can unlock ("Katana of Power"), indexed by the value of the first
+// special (SpecialDash1 in the bindings).
+var ITEM_SUFFIXES []string = []string{
+	"",
+	"of Power", "of Giant", "of Titans", "of Skill", "of Perfection", "of Brilliance", "of Enlightenment",
+	"of Protection", "of Anger", "of Rage", "of Fury", "of Vitriol", "of the Fox", "of Detection",
+	"of Reflection", "of the Twins",
+}
+
+// First words of the name prefixes that items and beasts carry ("Agony Bane Katana"), indexed by the value
+// of the second special (Special0 in the bindings).
+var NAME_PREFIXES []string = []string{
+	"",
+	"Agony", "Apocalypse", "Armageddon", "Beast", "Behemoth", "Blight", "Blood", "Bramble", "Brimstone",
+	"Brood", "Carrion", "Cataclysm", "Chimeric", "Corpse", "Corruption", "Damnation", "Death", "Demon",
+	"Dire", "Dragon", "Dread", "Doom", "Dusk", "Eagle", "Empyrean", "Fate", "Foe", "Gale", "Ghoul", "Gloom",
+	"Glyph", "Golem", "Grim", "Hate", "Havoc", "Honour", "Horror", "Hypnotic", "Kraken", "Loath",
+	"Maelstrom", "Mind", "Miracle", "Morbid", "Oblivion", "Onslaught", "Pain", "Pandemonium", "Phoenix",
+	"Plague", "Rage", "Rapture", "Rune", "Skull", "Sol", "Soul", "Sorrow", "Spirit", "Storm", "Tempest",
+	"Torment", "Vengeance", "Victory", "Viper", "Vortex", "Woe", "Wrath", "Light's", "Shimmering",
+}
+
+// Second words of the name prefixes, indexed by the value of the third special (Special1 in the
+// bindings).
+var NAME_SUFFIXES []string = []string{
+	"",
+	"Bane", "Root", "Bite", "Song", "Roar", "Grasp", "Instrument", "Glow", "Bender", "Shadow", "Whisper",
+	"Shout", "Growl", "Tear", "Peak", "Form", "Sun", "Moon",
+}
+
+// Returns the entry of a name table for the given ID, or an empty string if the table has no entry for
+// it.
+func lookupName(table []string, id uint64) string {
+	if id >= uint64(len(table)) {
+		return ""
+	}
+	return table[id]
+}
+
+// Returns the static description of the Loot item with the given ID, and false if there is no such item.
+func LookupItem(itemID uint64) (ItemInfo, bool) {
+	if itemID == 0 || itemID >= uint64(len(ITEMS)) {
+		return ItemInfo{}, false
+	}
+	return ITEMS[itemID], true
+}
+
+// Returns the name of the Loot item with the given ID (e.g. "Katana" for 42), or "item <ID>" if there is
+// no such item.
+func ItemName(itemID uint64) string {
+	item, ok := LookupItem(itemID)
+	if !ok {
+		return fmt.Sprintf("item %d", itemID)
+	}
+	return item.Name
+}
+
+// Returns the tier and type of a beast or obstacle from its ID. Both beasts and obstacles are numbered in
+// three groups of 25 (magical, then blade, then bludgeon), each ordered from T1 to T5 in blocks of 5.
+func EncounterTierAndType(id uint64) (Combat_Constants_CombatEnums_Tier, Combat_Constants_CombatEnums_Type) {
+	if id == 0 || id > 75 {
+		return 0, 0
+	}
+	return (id-1)%25/5 + 1, (id-1)/25 + 1
+}
+
+// Returns the name of the beast with the given ID (e.g. "Warlock" for 1), or "beast <ID>" if the beast is
+// not known.
+func BeastName(beastID uint64) string {
+	name := lookupName(BEAST_NAMES, beastID)
+	if name == "" {
+		return fmt.Sprintf("beast %d", beastID)
+	}
+	return name
+}
+
+// Returns the name of the obstacle with the given ID (e.g. "Demonic Alter" for 1), or "obstacle <ID>" if
+// the obstacle's name is not known.
+func ObstacleName(obstacleID uint64) string {
+	name := lookupName(OBSTACLE_NAMES, obstacleID)
+	if name == "" {
+		return fmt.Sprintf("obstacle %d", obstacleID)
+	}
+	return name
+}
+
+// Returns the name prefix made up of the second and third specials of an item or beast (e.g. "Agony
+// Bane"), or an empty string if neither is set.
+func SpecialsPrefix(special2, special3 uint64) string {
+	words := []string{}
+	for _, word := range []string{lookupName(NAME_PREFIXES, special2), lookupName(NAME_SUFFIXES, special3)} {
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// Returns the full name of an item with the given specials, e.g. "Agony Bane" Katana of Power. The prefix
+// and suffix are only included if the item has unlocked them.
+func FullItemName(itemID uint64, specials Survivor_ItemMeta_ItemSpecials) string {
+	name := ItemName(itemID)
+	if prefix := SpecialsPrefix(specials.Special0, specials.Special1); prefix != "" {
+		name = fmt.Sprintf("%q %s", prefix, name)
+	}
+	if suffix := lookupName(ITEM_SUFFIXES, specials.SpecialDash1); suffix != "" {
+		name = name + " " + suffix
+	}
+	return name
+}
+
+// Returns the full name of a beast with the given special powers, e.g. "Agony Bane Warlock".
+func FullBeastName(beastID uint64, specials Combat_Combat_SpecialPowers) string {
+	name := BeastName(beastID)
+	if prefix := SpecialsPrefix(specials.Special0, specials.Special1); prefix != "" {
+		name = prefix + " " + name
+	}
+	return name
+}
+
+// Fields of parsed events which hold lists of item IDs.
+var ITEM_LIST_FIELDS map[string]bool = map[string]bool{
+	"ItemIds":         true,
+	"Items":           true,
+	"EquippedItems":   true,
+	"UnequippedItems": true,
+}
+
+// Returns the names of the items, beasts, obstacles and specials that a parsed event refers to by ID,
+// keyed by the path of the field holding the ID within the event (e.g. "AdventurerState.Adventurer.Weapon.Id"
+// or "ItemIds.0"). Fields are recognized by the shape of the structs that contain them, so this works for
+// every event of the LootSurvivor contract. IDs of 0 (no item, beast or obstacle) are skipped, as are
+// specials that are not set. The name of an adventurer, which the contract stores as a Cairo short string,
+// is decoded (and escaped, see ShortStringName) under the path of its field (e.g. "AdventurerMeta.Name").
+func EventNames(event json.RawMessage) (map[string]string, error) {
+	payload, decodeErr := decodePayload(event)
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	names := make(map[string]string)
+	collectNames(payload, "", names)
+	return names, nil
+}
+
+// Decodes an event payload with json.Decoder.UseNumber, so that large integers are not rounded.
+func decodePayload(event json.RawMessage) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(event))
+	decoder.UseNumber()
+	var payload interface{}
+	decodeErr := decoder.Decode(&payload)
+	return payload, decodeErr
+}
+
+// Returns the value of a JSON number as a uint64, and false if the value is not a non-negative integer.
+func jsonUint64(value interface{}) (uint64, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	result, parseErr := strconv.ParseUint(number.String(), 10, 64)
+	return result, parseErr == nil
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func collectNames(value interface{}, path string, names map[string]string) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		has := func(field string) bool {
+			_, ok := typedValue[field]
+			return ok
+		}
+		addName := func(field string, table func(uint64) (string, bool)) {
+			id, ok := jsonUint64(typedValue[field])
+			if !ok || id == 0 {
+				return
+			}
+			if name, known := table(id); known {
+				names[joinPath(path, field)] = name
+			}
+		}
+
+		switch {
+		case has("BeastSpecs") || (has("StartingHealth") && has("CombatSpec")):
+			addName("Id", tableName(BEAST_NAMES))
+		case has("DamageTaken") && has("DamageLocation"):
+			addName("Id", tableName(OBSTACLE_NAMES))
+		case (has("Xp") && has("Metadata")) || (has("ItemType") && has("Slot")):
+			addName("Id", knownItemName)
+		case has("SpecialDash1") && has("Special0") && has("Special1"):
+			addName("SpecialDash1", tableName(ITEM_SUFFIXES))
+			addName("Special0", tableName(NAME_PREFIXES))
+			addName("Special1", tableName(NAME_SUFFIXES))
+		}
+		if has("StartBlock") && has("Name") {
+			if number, ok := typedValue["Name"].(json.Number); ok {
+				if name, isInteger := new(big.Int).SetString(number.String(), 10); isInteger && name.Sign() != 0 {
+					names[joinPath(path, "Name")] = ShortStringName(name)
+				}
+			}
+		}
+		addName("ItemId", knownItemName)
+		addName("KilledByBeast", tableName(BEAST_NAMES))
+		addName("KilledByObstacle", tableName(OBSTACLE_NAMES))
+
+		fields := make([]string, 0, len(typedValue))
+		for field := range typedValue {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			if list, ok := typedValue[field].([]interface{}); ok && ITEM_LIST_FIELDS[field] {
+				for i, element := range list {
+					if itemID, isID := jsonUint64(element); isID {
+						if name, known := knownItemName(itemID); known {
+							names[joinPath(path, fmt.Sprintf("%s.%d", field, i))] = name
+						}
+					}
+				}
+			}
+			collectNames(typedValue[field], joinPath(path, field), names)
+		}
+	case []interface{}:
+		for i, element := range typedValue {
+			collectNames(element, joinPath(path, strconv.Itoa(i)), names)
+		}
+	}
+}
+
+func knownItemName(itemID uint64) (string, bool) {
+	item, ok := LookupItem(itemID)
+	return item.Name, ok
+}
+
+func tableName(table []string) func(uint64) (string, bool) {
+	return func(id uint64) (string, bool) {
+		name := lookupName(table, id)
+		return name, name != ""
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEncounterNames(t *testing.T) {
+	// The names of these obstacles are not in OBSTACLE_NAMES yet. Remove them from here as they are filled
+	// in from the contract's obstacle constants.
+	unnamedObstacles := map[uint64]bool{15: true, 16: true, 17: true, 18: true, 19: true, 20: true, 21: true, 22: true, 23: true, 24: true, 25: true}
+
+	if len(BEAST_NAMES) != 76 || len(OBSTACLE_NAMES) != 76 {
+		t.Fatalf("expected entries for IDs 0 to 75, got %d beasts and %d obstacles", len(BEAST_NAMES), len(OBSTACLE_NAMES))
+	}
+
+	seen := map[string]uint64{}
+	for id := uint64(1); id <= 75; id++ {
+		if name := BeastName(id); name == fmt.Sprintf("beast %d", id) {
+			t.Errorf("beast %d has no name", id)
+		}
+
+		name := ObstacleName(id)
+		if unnamedObstacles[id] {
+			if name != fmt.Sprintf("obstacle %d", id) {
+				t.Errorf("obstacle %d is named %q, so it should be removed from unnamedObstacles", id, name)
+			}
+			continue
+		}
+		if name == fmt.Sprintf("obstacle %d", id) {
+			t.Errorf("obstacle %d has no name", id)
+		}
+		if previous, ok := seen[name]; ok {
+			t.Errorf("obstacles %d and %d are both named %q", previous, id, name)
+		}
+		seen[name] = id
+	}
+
+	for _, c := range []struct {
+		name     string
+		expected string
+	}{
+		{BeastName(1), "Warlock"},
+		{BeastName(26), "Griffin"},
+		{BeastName(75), "Skeleton"},
+		{BeastName(76), "beast 76"},
+		{ObstacleName(1), "Demonic Alter"},
+		{ObstacleName(26), "Pendulum Blades"},
+		{ObstacleName(75), "Rolling Boulder"},
+		{ObstacleName(0), "obstacle 0"},
+	} {
+		if c.name != c.expected {
+			t.Errorf("expected %q, got %q", c.expected, c.name)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// Greatness at which an item unlocks its suffix ("of Power"), and at which it unlocks its name prefixes
+// ("Agony Bane"). Items cannot grow beyond MAX_GREATNESS.
+var SUFFIX_UNLOCK_GREATNESS uint64 = 15
+var PREFIXES_UNLOCK_GREATNESS uint64 = 19
+var MAX_GREATNESS uint64 = 20
+
+// Returns the level that the game derives from an amount of XP: the integer square root of the XP, with
+// a minimum of 1. This is the level of an adventurer, and (up to MAX_GREATNESS) the greatness of an item.
+func LevelFromXP(xp uint64) uint64 {
+	if xp == 0 {
+		return 1
+	}
+	// Newton's method, starting from a value which is at least the square root.
+	root := xp/2 + 1
+	next := (root + xp/root) / 2
+	for next < root {
+		root = next
+		next = (root + xp/root) / 2
+	}
+	return root
+}
+
+// Returns the level of the adventurer (see LevelFromXP).
+func (adventurer Survivor_Adventurer_Adventurer) Level() uint64 {
+	return LevelFromXP(adventurer.Xp)
+}
+
+// Returns the greatness of the item (see LevelFromXP), which is capped at MAX_GREATNESS.
+func (item Survivor_ItemPrimitive_ItemPrimitive) Greatness() uint64 {
+	greatness := LevelFromXP(item.Xp)
+	if greatness > MAX_GREATNESS {
+		return MAX_GREATNESS
+	}
+	return greatness
+}
+
+// Returns true if the item is great enough to have unlocked its suffix.
+func (item Survivor_ItemPrimitive_ItemPrimitive) SuffixUnlocked() bool {
+	return item.Greatness() >= SUFFIX_UNLOCK_GREATNESS
+}
+
+// Returns true if the item is great enough to have unlocked its name prefixes.
+func (item Survivor_ItemPrimitive_ItemPrimitive) PrefixesUnlocked() bool {
+	return item.Greatness() >= PREFIXES_UNLOCK_GREATNESS
+}
+
+// Returns a Core_Bool (0 or 1) for a bool, as the contract's events represent flags.
+func coreBool(value bool) Core_Bool {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// Returns the values that the game derives from the XP of the adventurers and items in an event payload
+// (decoded with json.Decoder.UseNumber), keyed by the path at which they would appear in the payload: the
+// Level of each adventurer (e.g. "AdventurerState.Adventurer.Level"), and the Greatness, SuffixUnlocked
+// and PrefixesUnlocked of each item (e.g. "AdventurerState.Adventurer.Weapon.Greatness"). The flags are 1
+// or 0, like the Core_Bool fields of the events. Empty item slots (with an Id of 0) are skipped.
+func DerivedFields(payload interface{}) map[string]uint64 {
+	derived := make(map[string]uint64)
+	collectDerivedFields(payload, "", derived)
+	return derived
+}
+
+func collectDerivedFields(value interface{}, path string, derived map[string]uint64) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		_, hasHealth := typedValue["Health"]
+		_, hasMetadata := typedValue["Metadata"]
+		xp, hasXP := jsonUint64(typedValue["Xp"])
+
+		if hasXP && hasHealth {
+			derived[joinPath(path, "Level")] = Survivor_Adventurer_Adventurer{Xp: xp}.Level()
+		} else if hasXP && hasMetadata {
+			itemID, _ := jsonUint64(typedValue["Id"])
+			if itemID != 0 {
+				item := Survivor_ItemPrimitive_ItemPrimitive{Id: itemID, Xp: xp}
+				derived[joinPath(path, "Greatness")] = item.Greatness()
+				derived[joinPath(path, "SuffixUnlocked")] = coreBool(item.SuffixUnlocked())
+				derived[joinPath(path, "PrefixesUnlocked")] = coreBool(item.PrefixesUnlocked())
+			}
+		}
+
+		for field, fieldValue := range typedValue {
+			collectDerivedFields(fieldValue, joinPath(path, field), derived)
+		}
+	case []interface{}:
+		for i, element := range typedValue {
+			collectDerivedFields(element, joinPath(path, strconv.Itoa(i)), derived)
+		}
+	}
+}
+
+// Returns the derived fields (see DerivedFields) of a parsed event.
+func EventDerivedFields(event json.RawMessage) (map[string]uint64, error) {
+	payload, decodeErr := decodePayload(event)
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	return DerivedFields(payload), nil
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLevelFromXP(t *testing.T) {
+	cases := []struct {
+		xp    uint64
+		level uint64
+	}{
+		{0, 1},
+		{1, 1},
+		{3, 1},
+		{4, 2},
+		{8, 2},
+		{9, 3},
+		{99, 9},
+		{100, 10},
+		{math.MaxUint64, math.MaxUint32},
+	}
+
+	for _, c := range cases {
+		if level := LevelFromXP(c.xp); level != c.level {
+			t.Errorf("expected level %d for %d XP, got %d", c.level, c.xp, level)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+var ErrNoProviders error = errors.New("no Starknet RPC provider URLs were specified")
+
+// RetryConfig controls how requests to Starknet RPC providers are retried.
+type RetryConfig struct {
+	// Number of times a failed request is retried before its error is considered fatal.
+	MaxRetries int
+	// Time to wait before the first retry. The wait doubles with each subsequent retry, up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// JSON-RPC error codes with which some providers signal that a client has exceeded its rate limit. Errors
+// with these codes are retried like 429 responses.
+var RATE_LIMIT_ERROR_CODES []int = []int{-32005, 429}
+
+// ProviderPool is a list of Starknet RPC providers which are used interchangeably. Requests made through
+// the pool which fail transiently (see IsTransientError) are retried with exponential backoff, and each
+// retry goes to the next provider in the list. A ProviderPool may be used from multiple goroutines.
+type ProviderPool struct {
+	URLs      []string
+	Providers []*rpc.Provider
+	Retry     RetryConfig
+	// Index of the provider that requests are currently sent to.
+	current int
+	mu      sync.Mutex
+}
+
+// Parses a list of provider URLs as they may be specified on the command line, where each value may itself
+// be a comma-separated list of URLs. If no URLs are specified, the URLs in the STARKNET_RPC_URL environment
+// variable are used instead.
+func ProviderURLs(values []string) []string {
+	if len(values) == 0 {
+		values = []string{os.Getenv("STARKNET_RPC_URL")}
+	}
+
+	var urls []string
+	for _, value := range values {
+		for _, url := range strings.Split(value, ",") {
+			url = strings.TrimSpace(url)
+			if url != "" {
+				urls = append(urls, url)
+			}
+		}
+	}
+	return urls
+}
+
+func NewProviderPool(providerURLs []string, retry RetryConfig) (*ProviderPool, error) {
+	if len(providerURLs) == 0 {
+		return nil, ErrNoProviders
+	}
+
+	pool := &ProviderPool{URLs: providerURLs, Providers: make([]*rpc.Provider, len(providerURLs)), Retry: retry}
+	for i, providerURL := range providerURLs {
+		client, clientErr := rpc.NewClient(providerURL)
+		if clientErr != nil {
+			return nil, clientErr
+		}
+		pool.Providers[i] = rpc.NewProvider(client)
+	}
+
+	return pool, nil
+}
+
+// Returns the time to wait before the given retry attempt (starting at 1). This is the exponential backoff for
+// the retry, with up to half of it replaced by random jitter so that clients which failed at the same time
+// do not all retry at the same time.
+func (retry RetryConfig) Backoff(attempt int) time.Duration {
+	wait := retry.InitialBackoff
+	for i := 1; i < attempt && wait < retry.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if retry.MaxBackoff > 0 && wait > retry.MaxBackoff {
+		wait = retry.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// Returns true if a request which failed with err may succeed if it is sent again: if the request failed
+// because of a network error or a timeout, or the provider responded with a 429 or 5xx status or with a
+// rate limiting error. Any other error (such as invalid parameters, a contract error, or a block that does
+// not exist) is the provider's answer to the request, and would only be returned again.
+func IsTransientError(err error) bool {
+	var httpErr ethrpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+
+	var rpcErr ethrpc.Error
+	if errors.As(err, &rpcErr) {
+		for _, code := range RATE_LIMIT_ERROR_CODES {
+			if rpcErr.ErrorCode() == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Network errors (including timeouts), and connections which were closed before the response was read
+	// in full.
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// Calls f with a provider from the pool. If f returns a transient error (see IsTransientError), waits
+// according to the pool's retry configuration, fails over to the next provider in the pool, and tries
+// again. Returns any other error straight away, the last error once the retries are exhausted, or the
+// context's error as soon as the context is done.
+func (pool *ProviderPool) Do(ctx context.Context, f func(provider *rpc.Provider) error) error {
+	var err error
+	pool.mu.Lock()
+	current := pool.current
+	pool.mu.Unlock()
+
+	for attempt := 0; attempt <= pool.Retry.MaxRetries; attempt++ {
+		if attempt > 0 {
+			wait := pool.Retry.Backoff(attempt)
+			fmt.Fprintf(os.Stderr, "Request to %s failed (attempt %d of %d), retrying in %s: %s\n", pool.URLs[current], attempt, pool.Retry.MaxRetries+1, wait, err.Error())
+
+			// Fails over to the next provider, unless another request has already done so.
+			pool.mu.Lock()
+			if pool.current == current {
+				pool.current = (pool.current + 1) % len(pool.Providers)
+			}
+			current = pool.current
+			pool.mu.Unlock()
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		err = f(pool.Providers[current])
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !IsTransientError(err) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("request to %s failed after %d attempts: %w", pool.URLs[current], pool.Retry.MaxRetries+1, err)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+type testRPCError struct {
+	code int
+}
+
+func (err testRPCError) Error() string  { return fmt.Sprintf("rpc error %d", err.code) }
+func (err testRPCError) ErrorCode() int { return err.code }
+
+func TestIsTransientError(t *testing.T) {
+	cases := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{"429 response", ethrpc.HTTPError{StatusCode: 429}, true},
+		{"500 response", ethrpc.HTTPError{StatusCode: 500}, true},
+		{"503 response", ethrpc.HTTPError{StatusCode: 503}, true},
+		{"400 response", ethrpc.HTTPError{StatusCode: 400}, false},
+		{"401 response", ethrpc.HTTPError{StatusCode: 401}, false},
+		{"block not found", testRPCError{code: STARKNET_ERROR_BLOCK_NOT_FOUND}, false},
+		{"invalid params", testRPCError{code: -32602}, false},
+		{"contract error", testRPCError{code: 40}, false},
+		{"rate limited", testRPCError{code: -32005}, true},
+		{"connection refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"wrapped network error", fmt.Errorf("request failed: %w", &net.OpError{Op: "read", Err: errors.New("connection reset")}), true},
+		{"truncated response", io.ErrUnexpectedEOF, true},
+		{"pending block", ErrPendingBlock, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if transient := IsTransientError(c.err); transient != c.transient {
+				t.Errorf("expected IsTransientError to return %v, got %v", c.transient, transient)
+			}
+		})
+	}
+}
+
+func TestProviderPoolDoRetriesOnlyTransientErrors(t *testing.T) {
+	var unavailableRequests, healthyRequests int32
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&unavailableRequests, 1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+
+	node := &fakeStarknetNode{head: 10, forks: make(map[uint64]uint64)}
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&healthyRequests, 1)
+		node.ServeHTTP(w, r)
+	}))
+	defer healthy.Close()
+
+	providers, poolErr := NewProviderPool([]string{unavailable.URL, healthy.URL}, RetryConfig{MaxRetries: 3})
+	if poolErr != nil {
+		t.Fatalf("could not create provider pool: %s", poolErr.Error())
+	}
+	ctx := context.Background()
+
+	// A 503 from the first provider fails over to the second.
+	var blockNumber uint64
+	doErr := providers.Do(ctx, func(provider *rpc.Provider) error {
+		var err error
+		blockNumber, err = provider.BlockNumber(ctx)
+		return err
+	})
+	if doErr != nil {
+		t.Fatalf("expected the request to succeed on the second provider, got: %s", doErr.Error())
+	}
+	if blockNumber != 10 || unavailableRequests != 1 || healthyRequests != 1 {
+		t.Fatalf("expected block 10 after one request to each provider, got block %d after %d and %d requests", blockNumber, unavailableRequests, healthyRequests)
+	}
+
+	// A block beyond the head is not retried.
+	doErr = providers.Do(ctx, func(provider *rpc.Provider) error {
+		_, err := BlockHash(ctx, provider, 11)
+		return err
+	})
+	if !IsBlockNotFound(doErr) {
+		t.Fatalf("expected a block not found error, got: %v", doErr)
+	}
+	if healthyRequests != 2 || unavailableRequests != 1 {
+		t.Fatalf("expected a single request for the missing block, got %d and %d requests in total", unavailableRequests, healthyRequests)
+	}
+}
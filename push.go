@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrPushFailed error = errors.New("push failed")
+
+// Base URL of the Moonstream API, to which leaderboards are pushed by default.
+var MOONSTREAM_API_URL string = "https://engineapi.moonstream.to"
+
+// Path, relative to the Moonstream API URL, to which the scores for a leaderboard are pushed.
+var LEADERBOARD_SCORES_PATH string = "/leaderboard/%s/scores"
+
+// Maximum number of bytes of a failed response's body that are included in the error.
+var MAX_PUSH_ERROR_BODY int64 = 4096
+
+// PushConfig controls how leaderboards are pushed to sinks over HTTP.
+type PushConfig struct {
+	// Maximum number of scores sent to the Moonstream Leaderboards API in a single request. Larger
+	// leaderboards are pushed in chunks. If ChunkSize is 0, the whole leaderboard is pushed in one request.
+	ChunkSize int
+	// Requests which fail with a network error, a 429 response, or a 5xx response are retried.
+	Retry RetryConfig
+	// Time after which a request which has not completed is abandoned, and retried as for a network error.
+	// If 0, the timeout of DefaultPushConfig is used, so that an unresponsive sink cannot block a push
+	// forever.
+	Timeout time.Duration
+}
+
+// Returns the configuration with which leaderboards are pushed by default.
+func DefaultPushConfig() PushConfig {
+	return PushConfig{
+		ChunkSize: 10000,
+		Retry: RetryConfig{
+			MaxRetries:     5,
+			InitialBackoff: time.Second,
+			MaxBackoff:     time.Minute,
+		},
+		Timeout: 2 * time.Minute,
+	}
+}
+
+// MoonstreamSink pushes leaderboards to the Moonstream Leaderboards API.
+type MoonstreamSink struct {
+	// Base URL of the Moonstream API (see MOONSTREAM_API_URL).
+	APIURL      string
+	AccessToken string
+	Config      PushConfig
+}
+
+// Pushes a leaderboard to the Moonstream Leaderboards API. If overwrite is true, the leaderboard replaces
+// the scores currently on the Moonstream leaderboard. Leaderboards with more than sink.Config.ChunkSize
+// scores are pushed in chunks, of which only the first overwrites the existing scores and the rest are
+// added to it. Responses with a non-2xx status are returned as errors (wrapping ErrPushFailed) which
+// include the body of the response.
+func (sink *MoonstreamSink) Push(leaderboardID string, leaderboard []LeaderboardScore, overwrite bool) error {
+	chunkSize := sink.Config.ChunkSize
+	if chunkSize <= 0 || chunkSize > len(leaderboard) {
+		chunkSize = len(leaderboard)
+	}
+
+	// An empty leaderboard is still pushed, as it may overwrite the existing scores.
+	for start := 0; start == 0 || start < len(leaderboard); start += chunkSize {
+		end := start + chunkSize
+		if end > len(leaderboard) {
+			end = len(leaderboard)
+		}
+
+		pushErr := sink.PushChunk(leaderboardID, leaderboard[start:end], overwrite && start == 0)
+		if pushErr != nil {
+			if start > 0 {
+				return fmt.Errorf("only %d of %d scores were pushed: %w", start, len(leaderboard), pushErr)
+			}
+			return pushErr
+		}
+		fmt.Fprintf(os.Stderr, "Pushed %d of %d scores to leaderboard %s\n", end, len(leaderboard), leaderboardID)
+
+		if chunkSize == 0 {
+			break
+		}
+	}
+
+	return nil
+}
+
+// Pushes scores to the Moonstream Leaderboards API in a single request.
+func (sink *MoonstreamSink) PushChunk(leaderboardID string, scores []LeaderboardScore, overwrite bool) error {
+	leaderboardURL := strings.TrimSuffix(sink.APIURL, "/") + fmt.Sprintf(LEADERBOARD_SCORES_PATH, url.PathEscape(leaderboardID))
+	u, parseErr := url.Parse(leaderboardURL)
+	if parseErr != nil {
+		return parseErr
+	}
+	queryParams := url.Values{}
+	queryParams.Set("normalize_addresses", "false")
+	if overwrite {
+		queryParams.Set("overwrite", "true")
+	} else {
+		queryParams.Set("overwrite", "false")
+	}
+	u.RawQuery = queryParams.Encode()
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encodeErr := encoder.Encode(scores)
+	if encodeErr != nil {
+		return encodeErr
+	}
+
+	return SendWithRetries(func() (*http.Request, error) {
+		req, setupErr := http.NewRequest("PUT", u.String(), bytes.NewReader(buf.Bytes()))
+		if setupErr != nil {
+			return nil, setupErr
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", sink.AccessToken))
+		return req, nil
+	}, sink.Config, fmt.Sprintf("Push to leaderboard %s", leaderboardID))
+}
+
+func (sink *MoonstreamSink) Close() error {
+	return nil
+}
+
+// Sends the requests created by newRequest until one succeeds, retrying according to config.Retry.
+// Requests which fail with a network error, a 429 response, or a 5xx response are retried, as are requests
+// which take longer than config.Timeout. Responses with any other non-2xx status are returned as errors
+// (wrapping ErrPushFailed) which include the body of the response. The description of the request is used
+// in log messages.
+func SendWithRetries(newRequest func() (*http.Request, error), config PushConfig, description string) error {
+	retry := config.Retry
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultPushConfig().Timeout
+	}
+	httpClient := &http.Client{Timeout: timeout}
+
+	var err error
+	var retryAfter time.Duration
+	for attempt := 0; attempt <= retry.MaxRetries; attempt++ {
+		if attempt > 0 {
+			wait := retry.Backoff(attempt)
+			if retryAfter > wait {
+				wait = retryAfter
+			}
+			fmt.Fprintf(os.Stderr, "%s failed (attempt %d of %d), retrying in %s: %s\n", description, attempt, retry.MaxRetries+1, wait, err.Error())
+			time.Sleep(wait)
+		}
+
+		req, setupErr := newRequest()
+		if setupErr != nil {
+			return setupErr
+		}
+
+		resp, apiErr := httpClient.Do(req)
+		if apiErr != nil {
+			err = apiErr
+			retryAfter = 0
+			continue
+		}
+
+		body, readErr := io.ReadAll(io.LimitReader(resp.Body, MAX_PUSH_ERROR_BODY))
+		resp.Body.Close()
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		if readErr != nil {
+			body = []byte(fmt.Sprintf("(could not read response body: %s)", readErr.Error()))
+		}
+
+		err = fmt.Errorf("%w: status %d: %s", ErrPushFailed, resp.StatusCode, bytes.TrimSpace(body))
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return err
+		}
+
+		// Rate limited responses may specify how many seconds to wait before retrying.
+		retryAfter = 0
+		retryAfterSeconds, retryAfterErr := strconv.Atoi(resp.Header.Get("Retry-After"))
+		if retryAfterErr == nil && retryAfterSeconds > 0 {
+			retryAfter = time.Duration(retryAfterSeconds) * time.Second
+		}
+	}
+
+	return fmt.Errorf("%s failed after %d attempts: %w", description, retry.MaxRetries+1, err)
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordedRequest is a request received by a recordingServer.
+type recordedRequest struct {
+	Method        string
+	Path          string
+	Query         string
+	Authorization string
+	Body          []byte
+}
+
+// recordingServer records the requests it receives, and answers the nth of them with statuses[n] (or 200
+// once the statuses run out).
+type recordingServer struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []recordedRequest
+}
+
+func newRecordingServer(t *testing.T, statuses ...int) (*recordingServer, *httptest.Server) {
+	recorder := &recordingServer{statuses: statuses}
+	server := httptest.NewServer(recorder)
+	t.Cleanup(server.Close)
+	return recorder, server
+}
+
+func (recorder *recordingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	recorder.mu.Lock()
+	status := http.StatusOK
+	if len(recorder.requests) < len(recorder.statuses) {
+		status = recorder.statuses[len(recorder.requests)]
+	}
+	recorder.requests = append(recorder.requests, recordedRequest{
+		Method:        r.Method,
+		Path:          r.URL.Path,
+		Query:         r.URL.RawQuery,
+		Authorization: r.Header.Get("Authorization"),
+		Body:          body,
+	})
+	recorder.mu.Unlock()
+
+	w.WriteHeader(status)
+	w.Write([]byte("response body\n"))
+}
+
+func (recorder *recordingServer) received() []recordedRequest {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return append([]recordedRequest{}, recorder.requests...)
+}
+
+func testPushConfig(chunkSize, maxRetries int) PushConfig {
+	return PushConfig{
+		ChunkSize: chunkSize,
+		Retry:     RetryConfig{MaxRetries: maxRetries, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+		Timeout:   5 * time.Second,
+	}
+}
+
+func testScores(n int) []LeaderboardScore {
+	scores := make([]LeaderboardScore, n)
+	for i := range scores {
+		scores[i] = LeaderboardScore{Rank: i + 1, Address: string(rune('a' + i)), Score: 100 - i, PointsData: map[string]interface{}{}}
+	}
+	return scores
+}
+
+func TestSendWithRetries(t *testing.T) {
+	cases := []struct {
+		name         string
+		statuses     []int
+		maxRetries   int
+		wantRequests int
+		wantErr      bool
+	}{
+		{"success", nil, 3, 1, false},
+		{"retries server errors", []int{503, 500}, 3, 3, false},
+		{"retries rate limits", []int{429}, 3, 2, false},
+		{"client errors are not retried", []int{400}, 3, 1, true},
+		{"gives up after the retries", []int{502, 502, 502}, 2, 3, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			recorder, server := newRecordingServer(t, c.statuses...)
+			err := SendWithRetries(func() (*http.Request, error) {
+				return http.NewRequest("POST", server.URL, nil)
+			}, testPushConfig(0, c.maxRetries), "Test request")
+
+			if c.wantErr {
+				if !errors.Is(err, ErrPushFailed) {
+					t.Fatalf("expected an error wrapping ErrPushFailed, got %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if requests := recorder.received(); len(requests) != c.wantRequests {
+				t.Fatalf("expected %d requests, got %d", c.wantRequests, len(requests))
+			}
+		})
+	}
+}
+
+func TestSendWithRetriesTimesOut(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		mu.Unlock()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	// Handlers must return before the server can be closed.
+	defer server.Close()
+	defer close(release)
+
+	config := testPushConfig(0, 1)
+	config.Timeout = 50 * time.Millisecond
+
+	start := time.Now()
+	err := SendWithRetries(func() (*http.Request, error) {
+		return http.NewRequest("POST", server.URL, nil)
+	}, config, "Test request")
+	if err == nil {
+		t.Fatal("expected a request to a hanging server to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("request to a hanging server took %s", elapsed)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts != 2 {
+		t.Fatalf("expected the timed out request to be retried once, got %d attempts", attempts)
+	}
+}
+
+func TestMoonstreamSinkPushesInChunks(t *testing.T) {
+	recorder, server := newRecordingServer(t)
+	sink := &MoonstreamSink{APIURL: server.URL + "/", AccessToken: "token", Config: testPushConfig(2, 0)}
+
+	scores := testScores(5)
+	if pushErr := sink.Push("leaderboard-id", scores, true); pushErr != nil {
+		t.Fatalf("could not push leaderboard: %v", pushErr)
+	}
+
+	requests := recorder.received()
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 chunks, got %d requests", len(requests))
+	}
+	var pushed []LeaderboardScore
+	for i, request := range requests {
+		if request.Method != "PUT" || request.Path != "/leaderboard/leaderboard-id/scores" {
+			t.Errorf("request %d: unexpected %s %s", i, request.Method, request.Path)
+		}
+		if request.Authorization != "Bearer token" {
+			t.Errorf("request %d: unexpected Authorization header %q", i, request.Authorization)
+		}
+		wantQuery := "normalize_addresses=false&overwrite=false"
+		if i == 0 {
+			wantQuery = "normalize_addresses=false&overwrite=true"
+		}
+		if request.Query != wantQuery {
+			t.Errorf("request %d: expected query %q, got %q", i, wantQuery, request.Query)
+		}
+
+		var chunk []LeaderboardScore
+		if decodeErr := json.Unmarshal(request.Body, &chunk); decodeErr != nil {
+			t.Fatalf("request %d: could not decode body: %v", i, decodeErr)
+		}
+		pushed = append(pushed, chunk...)
+	}
+	if !reflect.DeepEqual(pushed, scores) {
+		t.Fatalf("pushed scores differ from the leaderboard:\n%+v\n%+v", pushed, scores)
+	}
+}
+
+func TestMoonstreamSinkPushesEmptyLeaderboard(t *testing.T) {
+	recorder, server := newRecordingServer(t)
+	sink := &MoonstreamSink{APIURL: server.URL, Config: testPushConfig(2, 0)}
+
+	if pushErr := sink.Push("leaderboard-id", []LeaderboardScore{}, true); pushErr != nil {
+		t.Fatalf("could not push leaderboard: %v", pushErr)
+	}
+	if requests := recorder.received(); len(requests) != 1 || string(requests[0].Body) != "[]\n" {
+		t.Fatalf("expected a single request overwriting the leaderboard with no scores, got %+v", requests)
+	}
+}
+
+func TestMoonstreamSinkReportsPartialPush(t *testing.T) {
+	recorder, server := newRecordingServer(t, 200, 400)
+	sink := &MoonstreamSink{APIURL: server.URL, Config: testPushConfig(2, 0)}
+
+	pushErr := sink.Push("leaderboard-id", testScores(5), true)
+	if !errors.Is(pushErr, ErrPushFailed) {
+		t.Fatalf("expected an error wrapping ErrPushFailed, got %v", pushErr)
+	}
+	if len(recorder.received()) != 2 {
+		t.Fatalf("expected the push to stop at the failed chunk, got %d requests", len(recorder.received()))
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	recorder, server := newRecordingServer(t, 503)
+	sink, sinkErr := NewLeaderboardSink(SINK_WEBHOOK, server.URL, "token", testPushConfig(1, 1))
+	if sinkErr != nil {
+		t.Fatalf("could not create sink: %v", sinkErr)
+	}
+	defer sink.Close()
+
+	// The whole leaderboard is posted in one request, whatever the chunk size.
+	scores := testScores(3)
+	if pushErr := sink.Push("leaderboard-id", scores, true); pushErr != nil {
+		t.Fatalf("could not push leaderboard: %v", pushErr)
+	}
+
+	requests := recorder.received()
+	if len(requests) != 2 {
+		t.Fatalf("expected the failed request to be retried once, got %d requests", len(requests))
+	}
+	request := requests[1]
+	if request.Method != "POST" || request.Authorization != "Bearer token" {
+		t.Fatalf("unexpected request: %s with Authorization %q", request.Method, request.Authorization)
+	}
+	var payload WebhookPayload
+	if decodeErr := json.Unmarshal(request.Body, &payload); decodeErr != nil {
+		t.Fatalf("could not decode body: %v", decodeErr)
+	}
+	expected := WebhookPayload{LeaderboardID: "leaderboard-id", Overwrite: true, Scores: scores}
+	if !reflect.DeepEqual(payload, expected) {
+		t.Fatalf("expected payload %+v, got %+v", expected, payload)
+	}
+}
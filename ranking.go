@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// Tie-breakers by which entrants with equal scores can be ordered (see RankLeaderboard):
+//   - earliest: the entrant who reached their score first (in the earliest block) ranks higher
+//   - xp: the entrant with more XP ranks higher
+//   - level: the entrant with the higher level (see LevelFromXP) ranks higher
+//   - id: the entrant with the lower ID (adventurer ID, or owner address) ranks higher
+var TIE_BREAKER_EARLIEST string = "earliest"
+var TIE_BREAKER_XP string = "xp"
+var TIE_BREAKER_LEVEL string = "level"
+var TIE_BREAKER_ID string = "id"
+
+var ErrInvalidTieBreaker error = errors.New("invalid tie-breaker (expected \"earliest\", \"xp\", \"level\", or \"id\")")
+
+// TieBreakers is the data by which an entrant is ordered among the entrants with the same score.
+type TieBreakers struct {
+	// Block in which the entrant's score last changed.
+	ScoreBlock uint64
+	XP         uint64
+	// Numeric ID of the entrant: the adventurer ID for leaderboards of adventurers, or the address for
+	// leaderboards of owners.
+	ID *big.Int
+}
+
+// Checks that each of the tie-breakers is one of TIE_BREAKER_*.
+func ValidateTieBreakers(tieBreakers []string) error {
+	for _, tieBreaker := range tieBreakers {
+		if tieBreaker != TIE_BREAKER_EARLIEST && tieBreaker != TIE_BREAKER_XP && tieBreaker != TIE_BREAKER_LEVEL && tieBreaker != TIE_BREAKER_ID {
+			return fmt.Errorf("%w: %s", ErrInvalidTieBreaker, tieBreaker)
+		}
+	}
+	return nil
+}
+
+// Compares two entrants by a single tie-breaker. Returns a negative number if a ranks higher than b, a
+// positive number if b ranks higher than a, and 0 if the tie-breaker does not separate them.
+func compareTieBreaker(tieBreaker string, a, b TieBreakers) int {
+	switch tieBreaker {
+	case TIE_BREAKER_EARLIEST:
+		if a.ScoreBlock != b.ScoreBlock {
+			if a.ScoreBlock < b.ScoreBlock {
+				return -1
+			}
+			return 1
+		}
+	case TIE_BREAKER_XP:
+		if a.XP != b.XP {
+			if a.XP > b.XP {
+				return -1
+			}
+			return 1
+		}
+	case TIE_BREAKER_LEVEL:
+		aLevel, bLevel := LevelFromXP(a.XP), LevelFromXP(b.XP)
+		if aLevel != bLevel {
+			if aLevel > bLevel {
+				return -1
+			}
+			return 1
+		}
+	case TIE_BREAKER_ID:
+		// Entrants without an ID rank below those with one.
+		if a.ID == nil || b.ID == nil {
+			if a.ID != nil {
+				return -1
+			}
+			if b.ID != nil {
+				return 1
+			}
+			return 0
+		}
+		return a.ID.Cmp(b.ID)
+	}
+	return 0
+}
+
+// Sorts the leaderboard in place, by score from highest to lowest, and sets the rank of each entrant.
+// Entrants with equal scores are ordered by the given tie-breakers (see TIE_BREAKER_*), in turn. Entrants
+// that no tie-breaker separates share a rank, and are listed in order of ID and then of address so that
+// the output is always the same for the same leaderboard, even for entrants whose address is not known.
+func RankLeaderboard(leaderboard []LeaderboardScore, tieBreakers []string) {
+	compare := func(a, b LeaderboardScore) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		for _, tieBreaker := range tieBreakers {
+			result := compareTieBreaker(tieBreaker, a.TieBreakers, b.TieBreakers)
+			if result != 0 {
+				return result
+			}
+		}
+		return 0
+	}
+
+	sort.SliceStable(leaderboard, func(i, j int) bool {
+		result := compare(leaderboard[i], leaderboard[j])
+		if result != 0 {
+			return result < 0
+		}
+		result = compareTieBreaker(TIE_BREAKER_ID, leaderboard[i].TieBreakers, leaderboard[j].TieBreakers)
+		if result != 0 {
+			return result < 0
+		}
+		return leaderboard[i].Address < leaderboard[j].Address
+	})
+
+	for i := range leaderboard {
+		if i > 0 && compare(leaderboard[i-1], leaderboard[i]) == 0 {
+			leaderboard[i].Rank = leaderboard[i-1].Rank
+		} else {
+			leaderboard[i].Rank = i + 1
+		}
+	}
+}
+
+// Returns the integer value of an ID as it appears in the leaderboards (in decimal, or as a 0x-prefixed hex
+// string), or nil if it is not a valid integer.
+func ParseTieBreakerID(id string) *big.Int {
+	value, ok := big.NewInt(0).SetString(id, 0)
+	if !ok {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestRankLeaderboard(t *testing.T) {
+	// Entrants a and b share every tie-breaker, and c has no ID. Their scores are all equal, so only the
+	// tie-breakers separate them from each other.
+	leaderboard := func() []LeaderboardScore {
+		return []LeaderboardScore{
+			{Address: "low", Score: 10, TieBreakers: TieBreakers{ScoreBlock: 1, XP: 100, ID: big.NewInt(1)}},
+			{Address: "c", Score: 50, TieBreakers: TieBreakers{ScoreBlock: 3, XP: 15, ID: nil}},
+			{Address: "b", Score: 50, TieBreakers: TieBreakers{ScoreBlock: 5, XP: 9, ID: big.NewInt(8)}},
+			{Address: "a", Score: 50, TieBreakers: TieBreakers{ScoreBlock: 5, XP: 9, ID: big.NewInt(8)}},
+			{Address: "d", Score: 50, TieBreakers: TieBreakers{ScoreBlock: 4, XP: 16, ID: big.NewInt(2)}},
+			{Address: "high", Score: 90, TieBreakers: TieBreakers{ScoreBlock: 9, XP: 0, ID: big.NewInt(9)}},
+		}
+	}
+
+	cases := []struct {
+		name        string
+		tieBreakers []string
+		addresses   []string
+		ranks       []int
+	}{
+		{
+			// Entrants who share a rank are still listed in order of ID, and then of address.
+			name:        "no tie-breakers",
+			tieBreakers: nil,
+			addresses:   []string{"high", "d", "a", "b", "c", "low"},
+			ranks:       []int{1, 2, 2, 2, 2, 6},
+		},
+		{
+			name:        "earliest",
+			tieBreakers: []string{TIE_BREAKER_EARLIEST},
+			addresses:   []string{"high", "c", "d", "a", "b", "low"},
+			ranks:       []int{1, 2, 3, 4, 4, 6},
+		},
+		{
+			name:        "xp",
+			tieBreakers: []string{TIE_BREAKER_XP},
+			addresses:   []string{"high", "d", "c", "a", "b", "low"},
+			ranks:       []int{1, 2, 3, 4, 4, 6},
+		},
+		{
+			// d (XP 16) is level 4, while c (XP 15), a and b (XP 9) are level 3, so their IDs separate them.
+			name:        "level then id",
+			tieBreakers: []string{TIE_BREAKER_LEVEL, TIE_BREAKER_ID},
+			addresses:   []string{"high", "d", "a", "b", "c", "low"},
+			ranks:       []int{1, 2, 3, 3, 5, 6},
+		},
+		{
+			name:        "id",
+			tieBreakers: []string{TIE_BREAKER_ID},
+			addresses:   []string{"high", "d", "a", "b", "c", "low"},
+			ranks:       []int{1, 2, 3, 3, 5, 6},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ranked := leaderboard()
+			RankLeaderboard(ranked, c.tieBreakers)
+
+			addresses := make([]string, len(ranked))
+			ranks := make([]int, len(ranked))
+			for i, score := range ranked {
+				addresses[i] = score.Address
+				ranks[i] = score.Rank
+			}
+			if !reflect.DeepEqual(addresses, c.addresses) {
+				t.Errorf("expected order %v, got %v", c.addresses, addresses)
+			}
+			if !reflect.DeepEqual(ranks, c.ranks) {
+				t.Errorf("expected ranks %v, got %v", c.ranks, ranks)
+			}
+		})
+	}
+}
+
+func TestRankLeaderboardWithoutAddresses(t *testing.T) {
+	// Entrants built from maps come in a different order on each run. Entrants whose addresses are not known
+	// must still be listed, and ranked, in the same order every time.
+	for _, reversed := range []bool{false, true} {
+		leaderboard := []LeaderboardScore{
+			{Address: "", Score: 50, PointsData: map[string]interface{}{"adventurer_id": "7"}, TieBreakers: TieBreakers{ScoreBlock: 2, ID: big.NewInt(7)}},
+			{Address: "", Score: 50, PointsData: map[string]interface{}{"adventurer_id": "3"}, TieBreakers: TieBreakers{ScoreBlock: 1, ID: big.NewInt(3)}},
+		}
+		if reversed {
+			leaderboard[0], leaderboard[1] = leaderboard[1], leaderboard[0]
+		}
+
+		RankLeaderboard(leaderboard, []string{TIE_BREAKER_XP})
+		ids := []interface{}{leaderboard[0].PointsData["adventurer_id"], leaderboard[1].PointsData["adventurer_id"]}
+		if !reflect.DeepEqual(ids, []interface{}{"3", "7"}) {
+			t.Errorf("reversed=%t: expected adventurers in order [3 7], got %v", reversed, ids)
+		}
+		if leaderboard[0].Rank != 1 || leaderboard[1].Rank != 1 {
+			t.Errorf("reversed=%t: expected both adventurers to share rank 1, got %d and %d", reversed, leaderboard[0].Rank, leaderboard[1].Rank)
+		}
+
+		RankLeaderboard(leaderboard, []string{TIE_BREAKER_EARLIEST})
+		ids = []interface{}{leaderboard[0].PointsData["adventurer_id"], leaderboard[1].PointsData["adventurer_id"]}
+		if !reflect.DeepEqual(ids, []interface{}{"3", "7"}) || leaderboard[0].Rank != 1 || leaderboard[1].Rank != 2 {
+			t.Errorf("reversed=%t: expected adventurers 3 and 7 at ranks 1 and 2, got %v at %d and %d", reversed, ids, leaderboard[0].Rank, leaderboard[1].Rank)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TotalLeaderboardScoring configures how the total leaderboard scores events. It can be loaded from a
+// YAML or JSON file of the form:
+//
+//	events:
+//	  game::Game::SlayedBeast:
+//	    weight: 10
+//	    multipliers:
+//	      - field: BeastSpecs.Level
+//	      - field: BeastSpecs.Tier
+//	        values: {1: 5, 2: 4, 3: 3, 4: 2, 5: 1}
+//	  game::Game::DodgedObstacle:
+//	    weight: 9
+//
+// Events which are not listed are not scored.
+type TotalLeaderboardScoring struct {
+	Events map[string]EventScoring `yaml:"events" json:"events"`
+}
+
+// EventScoring configures how an event is scored. Each occurrence of the event scores its weight,
+// multiplied by each of its multipliers.
+type EventScoring struct {
+	Weight      int                 `yaml:"weight" json:"weight"`
+	Multipliers []ScoringMultiplier `yaml:"multipliers,omitempty" json:"multipliers,omitempty"`
+}
+
+// ScoringMultiplier multiplies the score of an event based on the value of a field in the event's payload.
+// Field is the path to the field, with the names of nested fields separated by dots (e.g.
+// "BeastSpecs.Level"). Field may also be the path of a value derived from XP (see DerivedFields), e.g.
+// "AdventurerState.Adventurer.Level". If Values is not specified, the score is multiplied by the value of
+// the field itself. Otherwise, the score is multiplied by the entry in Values for the value of the field
+// (in decimal), or by Default (which defaults to 1) if there is no such entry.
+type ScoringMultiplier struct {
+	Field   string         `yaml:"field" json:"field"`
+	Values  map[string]int `yaml:"values,omitempty" json:"values,omitempty"`
+	Default *int           `yaml:"default,omitempty" json:"default,omitempty"`
+}
+
+// Returns the scoring which the total leaderboard uses by default. This scores the events in
+// LootSurvivorLeaderboardEvents with the weights in TotalLeaderboardEventScores.
+func DefaultTotalLeaderboardScoring() TotalLeaderboardScoring {
+	scoring := TotalLeaderboardScoring{Events: make(map[string]EventScoring)}
+	for _, name := range LootSurvivorLeaderboardEvents {
+		weight, ok := TotalLeaderboardEventScores[name]
+		if !ok {
+			continue
+		}
+		scoring.Events[name] = EventScoring{Weight: weight}
+	}
+	return scoring
+}
+
+// Loads a scoring file (in YAML or JSON format - JSON being a subset of YAML) and validates it.
+func LoadTotalLeaderboardScoring(scoringFile string) (TotalLeaderboardScoring, error) {
+	var scoring TotalLeaderboardScoring
+
+	contents, readErr := os.ReadFile(scoringFile)
+	if readErr != nil {
+		return scoring, readErr
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(contents))
+	decoder.KnownFields(true)
+	decodeErr := decoder.Decode(&scoring)
+	if decodeErr != nil {
+		return scoring, fmt.Errorf("could not parse scoring file %s: %w", scoringFile, decodeErr)
+	}
+
+	validateErr := scoring.Validate()
+	if validateErr != nil {
+		return scoring, fmt.Errorf("invalid scoring file %s: %w", scoringFile, validateErr)
+	}
+
+	return scoring, nil
+}
+
+// Checks that every event in the scoring is one of the game contract's events (see GameEventNames), and
+// that every multiplier specifies a field.
+func (scoring TotalLeaderboardScoring) Validate() error {
+	for _, name := range scoring.EventNames() {
+		if !IsGameEvent(name) {
+			return fmt.Errorf("unknown event: %s", name)
+		}
+		for i, multiplier := range scoring.Events[name].Multipliers {
+			if multiplier.Field == "" {
+				return fmt.Errorf("multiplier %d for event %s does not specify a field", i, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns the names of the scored events, in sorted order.
+func (scoring TotalLeaderboardScoring) EventNames() []string {
+	names := make([]string, 0, len(scoring.Events))
+	for name := range scoring.Events {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Returns the score of a single occurrence of the given event. The event's payload is only decoded if it
+// is needed by a multiplier.
+func (scoring TotalLeaderboardScoring) EventScore(name string, rawEvent json.RawMessage) (int, error) {
+	eventScoring, ok := scoring.Events[name]
+	if !ok {
+		return 0, nil
+	}
+
+	score := eventScoring.Weight
+	if len(eventScoring.Multipliers) == 0 {
+		return score, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(rawEvent))
+	decoder.UseNumber()
+	var payload interface{}
+	decodeErr := decoder.Decode(&payload)
+	if decodeErr != nil {
+		return 0, decodeErr
+	}
+
+	var derived map[string]uint64
+	for _, multiplier := range eventScoring.Multipliers {
+		value, valueErr := PayloadFieldValue(payload, multiplier.Field)
+		if valueErr != nil {
+			// The field may be one that the game derives from XP, such as an adventurer's level.
+			if derived == nil {
+				derived = DerivedFields(payload)
+			}
+			derivedValue, isDerived := derived[multiplier.Field]
+			if !isDerived {
+				return 0, fmt.Errorf("could not score %s event: %w", name, valueErr)
+			}
+			value = new(big.Int).SetUint64(derivedValue)
+		}
+
+		if multiplier.Values == nil {
+			if !value.IsInt64() {
+				return 0, fmt.Errorf("could not score %s event: field %s is too large to use as a multiplier", name, multiplier.Field)
+			}
+			score *= int(value.Int64())
+			continue
+		}
+
+		factor, ok := multiplier.Values[value.String()]
+		if !ok {
+			factor = 1
+			if multiplier.Default != nil {
+				factor = *multiplier.Default
+			}
+		}
+		score *= factor
+	}
+
+	return score, nil
+}
+
+// Returns the integer value of the field at the given (dot-separated) path in an event payload which was
+// decoded with json.Decoder.UseNumber. Numeric strings, such as the hex strings in which felts are
+// represented, are parsed as integers.
+func PayloadFieldValue(payload interface{}, path string) (*big.Int, error) {
+	current := payload
+	for _, fieldName := range strings.Split(path, ".") {
+		fields, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("field %s not found in event", path)
+		}
+		current, ok = fields[fieldName]
+		if !ok {
+			return nil, fmt.Errorf("field %s not found in event", path)
+		}
+	}
+
+	var rawValue string
+	switch value := current.(type) {
+	case json.Number:
+		rawValue = value.String()
+	case string:
+		rawValue = value
+	default:
+		return nil, fmt.Errorf("field %s is not a number", path)
+	}
+
+	result, ok := big.NewInt(0).SetString(rawValue, 0)
+	if !ok {
+		return nil, fmt.Errorf("field %s is not an integer: %s", path, rawValue)
+	}
+	return result, nil
+}
+
+// Names of the events that the game contract emits, all of which EventParser parses.
+var GameEventNames []string = []string{
+	Event_Game_Game_AdventurerDied,
+	Event_Game_Game_AdventurerLeveledUp,
+	Event_Game_Game_AdventurerUpgraded,
+	Event_Game_Game_AmbushedByBeast,
+	Event_Game_Game_AttackedBeast,
+	Event_Game_Game_AttackedByBeast,
+	Event_Game_Game_DiscoveredBeast,
+	Event_Game_Game_DiscoveredGold,
+	Event_Game_Game_DiscoveredHealth,
+	Event_Game_Game_DodgedObstacle,
+	Event_Game_Game_DroppedItems,
+	Event_Game_Game_EquippedItems,
+	Event_Game_Game_FleeFailed,
+	Event_Game_Game_FleeSucceeded,
+	Event_Game_Game_GameEntropyRotatedEvent,
+	Event_Game_Game_HitByObstacle,
+	Event_Game_Game_IdleDeathPenalty,
+	Event_Game_Game_ItemsLeveledUp,
+	Event_Game_Game_NewHighScore,
+	Event_Game_Game_PriceChangeEvent,
+	Event_Game_Game_PurchasedItems,
+	Event_Game_Game_PurchasedPotions,
+	Event_Game_Game_RewardDistribution,
+	Event_Game_Game_SlayedBeast,
+	Event_Game_Game_StartGame,
+	Event_Game_Game_UpgradesAvailable,
+}
+
+// Returns true if the given name (e.g. "game::Game::SlayedBeast") is one of GameEventNames.
+func IsGameEvent(name string) bool {
+	for _, gameEventName := range GameEventNames {
+		if name == gameEventName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+func TestGameEventNamesAreParsed(t *testing.T) {
+	parser, parserErr := NewEventParser()
+	if parserErr != nil {
+		t.Fatal(parserErr)
+	}
+
+	for _, name := range GameEventNames {
+		segments := strings.Split(name, "::")
+		selector := utils.GetSelectorFromNameFelt(segments[len(segments)-1])
+		// An event without parameters fails to parse if the parser recognizes its selector.
+		parsed, parseErr := parser.Parse(RawEvent{PrimaryKey: selector})
+		if parseErr == nil && parsed.Name != name {
+			t.Errorf("EventParser does not parse %s events", name)
+		}
+	}
+
+	if IsGameEvent("game::Game::NoSuchEvent") || IsGameEvent("") {
+		t.Error("expected unknown event names not to be game events")
+	}
+}
+
+func TestLoadTotalLeaderboardScoring(t *testing.T) {
+	cases := []struct {
+		name     string
+		filename string
+		contents string
+		// Substring of the expected error, if loading should fail.
+		err string
+	}{
+		{
+			name:     "yaml",
+			filename: "scoring.yaml",
+			contents: `events:
+  game::Game::SlayedBeast:
+    weight: 10
+    multipliers:
+      - field: BeastSpecs.Level
+      - field: BeastSpecs.Tier
+        values: {1: 5, 2: 4}
+        default: 0
+  game::Game::DodgedObstacle:
+    weight: 9
+`,
+		},
+		{
+			name:     "json",
+			filename: "scoring.json",
+			contents: `{"events": {"game::Game::SlayedBeast": {"weight": 10, "multipliers": [{"field": "BeastSpecs.Level"}, {"field": "BeastSpecs.Tier", "values": {"1": 5, "2": 4}, "default": 0}]}, "game::Game::DodgedObstacle": {"weight": 9}}}`,
+		},
+		{
+			name:     "unknown event",
+			filename: "scoring.yaml",
+			contents: "events:\n  game::Game::SlayedBeasts:\n    weight: 10\n",
+			err:      "unknown event: game::Game::SlayedBeasts",
+		},
+		{
+			name:     "unknown field",
+			filename: "scoring.yaml",
+			contents: "events:\n  game::Game::SlayedBeast:\n    wieght: 10\n",
+			err:      "wieght",
+		},
+		{
+			name:     "multiplier without field",
+			filename: "scoring.yaml",
+			contents: "events:\n  game::Game::SlayedBeast:\n    weight: 10\n    multipliers:\n      - values: {1: 5}\n",
+			err:      "does not specify a field",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			scoringFile := filepath.Join(t.TempDir(), c.filename)
+			if writeErr := os.WriteFile(scoringFile, []byte(c.contents), 0644); writeErr != nil {
+				t.Fatal(writeErr)
+			}
+
+			scoring, loadErr := LoadTotalLeaderboardScoring(scoringFile)
+			if c.err != "" {
+				if loadErr == nil || !strings.Contains(loadErr.Error(), c.err) {
+					t.Fatalf("expected an error containing %q, got %v", c.err, loadErr)
+				}
+				return
+			}
+			if loadErr != nil {
+				t.Fatalf("could not load scoring: %s", loadErr.Error())
+			}
+
+			scores := []struct {
+				name     string
+				event    string
+				expected int
+			}{
+				{Event_Game_Game_SlayedBeast, `{"BeastSpecs": {"Level": 3, "Tier": 2}}`, 10 * 3 * 4},
+				{Event_Game_Game_SlayedBeast, `{"BeastSpecs": {"Level": 3, "Tier": 5}}`, 0},
+				{Event_Game_Game_DodgedObstacle, `{}`, 9},
+				{Event_Game_Game_FleeFailed, `{}`, 0},
+			}
+			for _, score := range scores {
+				eventScore, scoreErr := scoring.EventScore(score.name, json.RawMessage(score.event))
+				if scoreErr != nil {
+					t.Fatalf("could not score %s event: %s", score.name, scoreErr.Error())
+				}
+				if eventScore != score.expected {
+					t.Errorf("expected %s event %s to score %d, got %d", score.name, score.event, score.expected, eventScore)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultTotalLeaderboardScoringIsValid(t *testing.T) {
+	if validateErr := DefaultTotalLeaderboardScoring().Validate(); validateErr != nil {
+		t.Fatalf("default scoring is invalid: %s", validateErr.Error())
+	}
+}
@@ -4,6 +4,7 @@ LOOT_SURVIVOR_BINARY=${LOOT_SURVIVOR_BINARY:-"loot-survivor"}
 PROJECT_ROOT_DIR="$(dirname "$0")/.."
 DATA_DIR=${DATA_DIR:-"$PROJECT_ROOT_DIR/data"}
 UPDATE_INTERVAL=${CRAWL_INTERVAL:-1800}
+EVENT_STORE=${EVENT_STORE:-"$DATA_DIR/events.db"}
 
 if [ -z "$MOONSTREAM_ACCESS_TOKEN" ]
 then
@@ -27,10 +28,10 @@ set -e -o pipefail
 while true
 do
     echo "Updating Beast Slayers leaderboard"
-    cat "$DATA_DIR"/events-* | "$LOOT_SURVIVOR_BINARY" leaderboards beast-slayers -i - -o "$DATA_DIR/slayers.json" --push --leaderboard-id "$BEAST_SLAYERS_LEADERBOARD_ID"
+    "$LOOT_SURVIVOR_BINARY" leaderboards beast-slayers --store "$EVENT_STORE" -o "$DATA_DIR/slayers.json" --push --leaderboard-id "$BEAST_SLAYERS_LEADERBOARD_ID"
 
     echo "Updating Artful Dodgers leaderboard"
-    cat "$DATA_DIR"/events-* | "$LOOT_SURVIVOR_BINARY" leaderboards artful-dodgers -i - -o "$DATA_DIR/dodgers.json" --push --leaderboard-id "$ARTFUL_DODGERS_LEADERBOARD_ID"
+    "$LOOT_SURVIVOR_BINARY" leaderboards artful-dodgers --store "$EVENT_STORE" -o "$DATA_DIR/dodgers.json" --push --leaderboard-id "$ARTFUL_DODGERS_LEADERBOARD_ID"
 
     echo "Sleeping for $UPDATE_INTERVAL seconds"
     sleep "$UPDATE_INTERVAL"
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Maximum number of characters in a Cairo short string, so that its bytes fit in a felt252.
+var SHORT_STRING_MAX_LENGTH int = 31
+
+// The order of the field over which Starknet felts are defined (2^251 + 17 * 2^192 + 1). Every felt is
+// less than this.
+var FELT_PRIME *big.Int = feltPrime()
+
+var ErrShortStringTooLong error = errors.New("short string is longer than 31 characters")
+var ErrShortStringNotASCII error = errors.New("short string contains non-ASCII characters")
+var ErrInvalidEscape error = errors.New("invalid escape sequence in short string (expected \\\\ or \\xNN)")
+var ErrFeltOutOfRange error = errors.New("value is not a felt (it must be non-negative and less than the field prime)")
+
+func feltPrime() *big.Int {
+	prime := new(big.Int).Lsh(big.NewInt(1), 251)
+	prime.Add(prime, new(big.Int).Lsh(big.NewInt(17), 192))
+	return prime.Add(prime, big.NewInt(1))
+}
+
+// Returns the felt252 which encodes a Cairo short string: the big-endian integer formed by the string's
+// bytes. Short strings are made up of at most SHORT_STRING_MAX_LENGTH ASCII characters.
+func EncodeShortString(s string) (*big.Int, error) {
+	if len(s) > SHORT_STRING_MAX_LENGTH {
+		return nil, ErrShortStringTooLong
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] > 0x7f {
+			return nil, ErrShortStringNotASCII
+		}
+	}
+	return new(big.Int).SetBytes([]byte(s)), nil
+}
+
+// Returns the Cairo short string which a felt252 encodes (see EncodeShortString). A nil or zero value is
+// the empty string.
+//
+// Values with more than SHORT_STRING_MAX_LENGTH bytes cannot be short strings, and return
+// ErrShortStringTooLong. Values with bytes outside of ASCII (which Cairo does not produce, but which a
+// client may write to a felt all the same) are returned along with ErrShortStringNotASCII; use
+// EscapeShortString to display them.
+func DecodeShortString(value *big.Int) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+	if value.Sign() < 0 || value.BitLen() > 8*SHORT_STRING_MAX_LENGTH {
+		return "", ErrShortStringTooLong
+	}
+	decoded := string(value.Bytes())
+	for i := 0; i < len(decoded); i++ {
+		if decoded[i] > 0x7f {
+			return decoded, ErrShortStringNotASCII
+		}
+	}
+	return decoded, nil
+}
+
+// Returns a short string in a form that is safe to print: printable ASCII characters are kept as they are,
+// backslashes are written as \\, and every other byte is written as \xNN. UnescapeShortString reverses this.
+func EscapeShortString(s string) string {
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\':
+			builder.WriteString(`\\`)
+		case c >= 0x20 && c < 0x7f:
+			builder.WriteByte(c)
+		default:
+			fmt.Fprintf(&builder, `\x%02x`, c)
+		}
+	}
+	return builder.String()
+}
+
+// Returns the string which EscapeShortString escaped to the given form.
+func UnescapeShortString(escaped string) (string, error) {
+	var builder strings.Builder
+	for i := 0; i < len(escaped); i++ {
+		if escaped[i] != '\\' {
+			builder.WriteByte(escaped[i])
+			continue
+		}
+		if i+1 < len(escaped) && escaped[i+1] == '\\' {
+			builder.WriteByte('\\')
+			i++
+			continue
+		}
+		if i+3 < len(escaped) && escaped[i+1] == 'x' {
+			c, parseErr := strconv.ParseUint(escaped[i+2:i+4], 16, 8)
+			if parseErr == nil {
+				builder.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		return "", ErrInvalidEscape
+	}
+	return builder.String(), nil
+}
+
+// Returns a printable form of a felt252 that holds a short string, such as the name of an adventurer: the
+// escaped string (see EscapeShortString), or the value in hex if it is too large to be a short string.
+func ShortStringName(value *big.Int) string {
+	decoded, decodeErr := DecodeShortString(value)
+	if errors.Is(decodeErr, ErrShortStringTooLong) {
+		return fmt.Sprintf("0x%s", value.Text(16))
+	}
+	return EscapeShortString(decoded)
+}
+
+// FeltConversion is the form in which the "felt" command writes a value: as hex, as decimal, and as the
+// (escaped) short string it encodes, if it is short enough to encode one.
+type FeltConversion struct {
+	Hex         string
+	Decimal     string
+	ShortString *string `json:",omitempty"`
+}
+
+// Parses a felt from a 0x-prefixed hex string, a decimal string, or (if asShortString is true) an escaped
+// short string (see UnescapeShortString), and returns it in all three forms.
+func ConvertFelt(input string, asShortString bool) (FeltConversion, error) {
+	var value *big.Int
+	if asShortString {
+		unescaped, unescapeErr := UnescapeShortString(input)
+		if unescapeErr != nil {
+			return FeltConversion{}, unescapeErr
+		}
+		var encodeErr error
+		value, encodeErr = EncodeShortString(unescaped)
+		if encodeErr != nil {
+			return FeltConversion{}, encodeErr
+		}
+	} else {
+		var ok bool
+		if strings.HasPrefix(input, "0x") || strings.HasPrefix(input, "0X") {
+			value, ok = new(big.Int).SetString(input[2:], 16)
+		} else {
+			value, ok = new(big.Int).SetString(input, 10)
+		}
+		if !ok {
+			return FeltConversion{}, fmt.Errorf("could not parse %s as a hex (0x-prefixed) or decimal number", input)
+		}
+		if value.Sign() < 0 || value.Cmp(FELT_PRIME) >= 0 {
+			return FeltConversion{}, ErrFeltOutOfRange
+		}
+	}
+
+	conversion := FeltConversion{
+		Hex:     fmt.Sprintf("0x%s", value.Text(16)),
+		Decimal: value.String(),
+	}
+	decoded, decodeErr := DecodeShortString(value)
+	if !errors.Is(decodeErr, ErrShortStringTooLong) {
+		escaped := EscapeShortString(decoded)
+		conversion.ShortString = &escaped
+	}
+	return conversion, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestShortStringRoundTrip(t *testing.T) {
+	cases := []struct {
+		name    string
+		s       string
+		encoded string
+		escaped string
+	}{
+		{"empty", "", "0", ""},
+		{"name", "loaf", "0x6c6f6166", "loaf"},
+		{"backslash", `a\b`, "0x615c62", `a\\b`},
+		{"control characters", "a\nb\x7f", "0x610a627f", `a\x0ab\x7f`},
+		{"maximum length", strings.Repeat("z", 31), "0x" + strings.Repeat("7a", 31), strings.Repeat("z", 31)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			encoded, encodeErr := EncodeShortString(c.s)
+			if encodeErr != nil {
+				t.Fatalf("could not encode short string: %s", encodeErr.Error())
+			}
+			expected, _ := new(big.Int).SetString(c.encoded, 0)
+			if encoded.Cmp(expected) != 0 {
+				t.Errorf("expected %q to encode to %s, got 0x%s", c.s, c.encoded, encoded.Text(16))
+			}
+
+			decoded, decodeErr := DecodeShortString(encoded)
+			if decodeErr != nil || decoded != c.s {
+				t.Errorf("expected %s to decode to %q, got %q (error: %v)", c.encoded, c.s, decoded, decodeErr)
+			}
+
+			escaped := EscapeShortString(decoded)
+			if escaped != c.escaped {
+				t.Errorf("expected %q to escape to %s, got %s", c.s, c.escaped, escaped)
+			}
+			unescaped, unescapeErr := UnescapeShortString(escaped)
+			if unescapeErr != nil || unescaped != c.s {
+				t.Errorf("expected %s to unescape to %q, got %q (error: %v)", escaped, c.s, unescaped, unescapeErr)
+			}
+		})
+	}
+}
+
+func TestShortStringErrors(t *testing.T) {
+	if _, encodeErr := EncodeShortString(strings.Repeat("z", 32)); !errors.Is(encodeErr, ErrShortStringTooLong) {
+		t.Errorf("expected a 32 character string to be too long, got %v", encodeErr)
+	}
+	if _, encodeErr := EncodeShortString("café"); !errors.Is(encodeErr, ErrShortStringNotASCII) {
+		t.Errorf("expected a non-ASCII string to be rejected, got %v", encodeErr)
+	}
+
+	tooLong := new(big.Int).Lsh(big.NewInt(1), 8*31)
+	if _, decodeErr := DecodeShortString(tooLong); !errors.Is(decodeErr, ErrShortStringTooLong) {
+		t.Errorf("expected a 32 byte value to be too long, got %v", decodeErr)
+	}
+	if name := ShortStringName(tooLong); name != "0x1"+strings.Repeat("00", 31) {
+		t.Errorf("expected a 32 byte value to be named in hex, got %s", name)
+	}
+
+	// Bytes outside of ASCII are decoded all the same, and escaped for display.
+	nonASCII := new(big.Int).SetBytes([]byte("caf\xc3\xa9"))
+	decoded, decodeErr := DecodeShortString(nonASCII)
+	if !errors.Is(decodeErr, ErrShortStringNotASCII) || decoded != "caf\xc3\xa9" {
+		t.Errorf("expected the non-ASCII value to decode with an error, got %q (error: %v)", decoded, decodeErr)
+	}
+	if name := ShortStringName(nonASCII); name != `caf\xc3\xa9` {
+		t.Errorf("expected the non-ASCII value to be escaped, got %s", name)
+	}
+	unescaped, unescapeErr := UnescapeShortString(`caf\xc3\xa9`)
+	if unescapeErr != nil || unescaped != "caf\xc3\xa9" {
+		t.Errorf("expected the escaped value to unescape to its bytes, got %q (error: %v)", unescaped, unescapeErr)
+	}
+
+	for _, invalid := range []string{`\`, `\x`, `\x4`, `\xzz`, `a\b`} {
+		if _, unescapeErr := UnescapeShortString(invalid); !errors.Is(unescapeErr, ErrInvalidEscape) {
+			t.Errorf("expected %s to be an invalid escape, got %v", invalid, unescapeErr)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// Kinds of sink to which leaderboards can be pushed.
+var SINK_MOONSTREAM string = "moonstream"
+var SINK_CSV string = "csv"
+var SINK_SQL string = "sql"
+var SINK_WEBHOOK string = "webhook"
+
+var ErrInvalidSink error = errors.New("invalid sink (expected \"moonstream\", \"csv\", \"sql\", or \"webhook\")")
+var ErrSinkTargetRequired error = errors.New("this sink requires a target (use --sink-target)")
+
+// LeaderboardSink is a destination to which leaderboards are pushed.
+type LeaderboardSink interface {
+	// Pushes a leaderboard to the sink under the given leaderboard ID. If overwrite is true, the
+	// leaderboard replaces any scores that the sink holds for that leaderboard ID.
+	Push(leaderboardID string, leaderboard []LeaderboardScore, overwrite bool) error
+	Close() error
+}
+
+// Creates a sink of the given kind (see SINK_*). The meaning of the target depends on the kind of sink:
+//   - moonstream: the base URL of the Moonstream API (defaults to MOONSTREAM_API_URL)
+//   - csv: the file to which the leaderboards are written ("-" for stdout, in which case nothing else may
+//     be written to stdout)
+//   - sql: the path of a SQLite database, or the URL of a Postgres database ("postgres://...")
+//   - webhook: the URL to which the leaderboards are posted
+//
+// The access token is sent as a bearer token by the moonstream sink and, if it is not empty, by the
+// webhook sink.
+func NewLeaderboardSink(kind, target, accessToken string, config PushConfig) (LeaderboardSink, error) {
+	switch kind {
+	case SINK_MOONSTREAM:
+		if target == "" {
+			target = MOONSTREAM_API_URL
+		}
+		return &MoonstreamSink{APIURL: target, AccessToken: accessToken, Config: config}, nil
+	case SINK_CSV:
+		if target == "" {
+			return nil, ErrSinkTargetRequired
+		}
+		return NewCSVSink(target)
+	case SINK_SQL:
+		if target == "" {
+			return nil, ErrSinkTargetRequired
+		}
+		return NewSQLSink(target)
+	case SINK_WEBHOOK:
+		if target == "" {
+			return nil, ErrSinkTargetRequired
+		}
+		return &WebhookSink{URL: target, AccessToken: accessToken, Config: config}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSink, kind)
+	}
+}
+
+// CSVSink writes leaderboards to a CSV file, with one row per score. The file is truncated when the sink
+// is created, and every leaderboard pushed to the sink is written to it, so each row records the ID of
+// its leaderboard. The points data of each score is written as a JSON object.
+type CSVSink struct {
+	File   *os.File
+	Writer *csv.Writer
+}
+
+// Header row of the files written by CSVSink.
+var CSV_SINK_HEADER []string = []string{"leaderboard_id", "rank", "address", "score", "points_data"}
+
+func NewCSVSink(csvFile string) (*CSVSink, error) {
+	ofp := os.Stdout
+	if csvFile != "-" {
+		var createErr error
+		ofp, createErr = os.Create(csvFile)
+		if createErr != nil {
+			return nil, createErr
+		}
+	}
+
+	sink := &CSVSink{File: ofp, Writer: csv.NewWriter(ofp)}
+	headerErr := sink.Writer.Write(CSV_SINK_HEADER)
+	if headerErr != nil {
+		sink.Close()
+		return nil, headerErr
+	}
+	return sink, nil
+}
+
+// Writes the leaderboard to the CSV file. As the file only ever holds the leaderboards pushed since the
+// sink was created, overwrite has no effect.
+func (sink *CSVSink) Push(leaderboardID string, leaderboard []LeaderboardScore, overwrite bool) error {
+	for _, score := range leaderboard {
+		pointsData, marshalErr := json.Marshal(score.PointsData)
+		if marshalErr != nil {
+			return marshalErr
+		}
+		writeErr := sink.Writer.Write([]string{leaderboardID, strconv.Itoa(score.Rank), score.Address, strconv.Itoa(score.Score), string(pointsData)})
+		if writeErr != nil {
+			return writeErr
+		}
+	}
+
+	sink.Writer.Flush()
+	return sink.Writer.Error()
+}
+
+func (sink *CSVSink) Close() error {
+	sink.Writer.Flush()
+	if sink.File == os.Stdout {
+		return sink.Writer.Error()
+	}
+	closeErr := sink.File.Close()
+	if closeErr != nil {
+		return closeErr
+	}
+	return sink.Writer.Error()
+}
+
+// Schema of the table to which SQLSink writes leaderboards. The statement is valid for both SQLite and
+// Postgres.
+var SQL_SINK_SCHEMA string = `
+CREATE TABLE IF NOT EXISTS leaderboard_scores (
+	leaderboard_id TEXT NOT NULL,
+	address TEXT NOT NULL,
+	rank BIGINT NOT NULL,
+	score BIGINT NOT NULL,
+	points_data TEXT NOT NULL,
+	updated_at BIGINT NOT NULL,
+	PRIMARY KEY (leaderboard_id, address)
+)`
+
+// SQLSink upserts leaderboards into the leaderboard_scores table (see SQL_SINK_SCHEMA) of a SQLite or
+// Postgres database, creating the table if it does not exist. Each leaderboard is written in a single
+// transaction, so readers of the table never see a partially written leaderboard.
+type SQLSink struct {
+	DB *sql.DB
+	// Whether the database is a Postgres database, which uses different placeholders in statements.
+	Postgres bool
+}
+
+// Opens the database to which a SQLSink writes. Targets that begin with "postgres://" or "postgresql://"
+// are Postgres URLs, and any other target is the path of a SQLite database.
+func NewSQLSink(target string) (*SQLSink, error) {
+	sink := &SQLSink{Postgres: strings.HasPrefix(target, "postgres://") || strings.HasPrefix(target, "postgresql://")}
+
+	var openErr error
+	if sink.Postgres {
+		sink.DB, openErr = sql.Open("postgres", target)
+	} else {
+		sink.DB, openErr = sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=journal_mode(WAL)&_pragma=busy_timeout(60000)", target))
+	}
+	if openErr != nil {
+		return nil, openErr
+	}
+
+	_, schemaErr := sink.DB.Exec(SQL_SINK_SCHEMA)
+	if schemaErr != nil {
+		sink.DB.Close()
+		return nil, schemaErr
+	}
+
+	return sink, nil
+}
+
+// Rewrites the ? placeholders in a statement to the $1, $2, ... placeholders that Postgres expects.
+func (sink *SQLSink) rebind(statement string) string {
+	if !sink.Postgres {
+		return statement
+	}
+	var result strings.Builder
+	placeholder := 0
+	for _, c := range statement {
+		if c == '?' {
+			placeholder++
+			result.WriteString("$" + strconv.Itoa(placeholder))
+		} else {
+			result.WriteRune(c)
+		}
+	}
+	return result.String()
+}
+
+// Upserts the scores of the leaderboard. If overwrite is true, scores for addresses which are no longer on
+// the leaderboard are removed.
+func (sink *SQLSink) Push(leaderboardID string, leaderboard []LeaderboardScore, overwrite bool) error {
+	tx, txErr := sink.DB.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	defer tx.Rollback()
+
+	if overwrite {
+		_, deleteErr := tx.Exec(sink.rebind("DELETE FROM leaderboard_scores WHERE leaderboard_id = ?"), leaderboardID)
+		if deleteErr != nil {
+			return deleteErr
+		}
+	}
+
+	upsertStmt, prepareErr := tx.Prepare(sink.rebind(`INSERT INTO leaderboard_scores (leaderboard_id, address, rank, score, points_data, updated_at)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (leaderboard_id, address) DO UPDATE SET rank = excluded.rank, score = excluded.score, points_data = excluded.points_data, updated_at = excluded.updated_at`))
+	if prepareErr != nil {
+		return prepareErr
+	}
+	defer upsertStmt.Close()
+
+	updatedAt := time.Now().Unix()
+	for _, score := range leaderboard {
+		pointsData, marshalErr := json.Marshal(score.PointsData)
+		if marshalErr != nil {
+			return marshalErr
+		}
+		_, upsertErr := upsertStmt.Exec(leaderboardID, score.Address, score.Rank, score.Score, string(pointsData), updatedAt)
+		if upsertErr != nil {
+			return upsertErr
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (sink *SQLSink) Close() error {
+	return sink.DB.Close()
+}
+
+// WebhookSink posts each leaderboard, as a WebhookPayload, to a URL. Requests are retried in the same way
+// as pushes to the Moonstream Leaderboards API.
+type WebhookSink struct {
+	URL string
+	// If not empty, sent as a bearer token in the Authorization header.
+	AccessToken string
+	// The configuration with which requests are sent. As the whole leaderboard is sent in a single
+	// request, ChunkSize is not used.
+	Config PushConfig
+}
+
+// WebhookPayload is the body of the requests that WebhookSink sends.
+type WebhookPayload struct {
+	LeaderboardID string             `json:"leaderboard_id"`
+	Overwrite     bool               `json:"overwrite"`
+	Scores        []LeaderboardScore `json:"scores"`
+}
+
+func (sink *WebhookSink) Push(leaderboardID string, leaderboard []LeaderboardScore, overwrite bool) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encodeErr := encoder.Encode(WebhookPayload{LeaderboardID: leaderboardID, Overwrite: overwrite, Scores: leaderboard})
+	if encodeErr != nil {
+		return encodeErr
+	}
+
+	return SendWithRetries(func() (*http.Request, error) {
+		req, setupErr := http.NewRequest("POST", sink.URL, bytes.NewReader(buf.Bytes()))
+		if setupErr != nil {
+			return nil, setupErr
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if sink.AccessToken != "" {
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", sink.AccessToken))
+		}
+		return req, nil
+	}, sink.Config, fmt.Sprintf("Webhook for leaderboard %s", leaderboardID))
+}
+
+func (sink *WebhookSink) Close() error {
+	return nil
+}
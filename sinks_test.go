@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewLeaderboardSinkRequiresTarget(t *testing.T) {
+	for _, kind := range []string{SINK_CSV, SINK_SQL, SINK_WEBHOOK} {
+		_, sinkErr := NewLeaderboardSink(kind, "", "", DefaultPushConfig())
+		if !errors.Is(sinkErr, ErrSinkTargetRequired) {
+			t.Errorf("%s sink: expected ErrSinkTargetRequired, got %v", kind, sinkErr)
+		}
+	}
+
+	_, sinkErr := NewLeaderboardSink("ftp", "target", "", DefaultPushConfig())
+	if !errors.Is(sinkErr, ErrInvalidSink) {
+		t.Errorf("expected ErrInvalidSink, got %v", sinkErr)
+	}
+}
+
+func TestCSVSink(t *testing.T) {
+	csvFile := filepath.Join(t.TempDir(), "leaderboards.csv")
+	sink, sinkErr := NewLeaderboardSink(SINK_CSV, csvFile, "", DefaultPushConfig())
+	if sinkErr != nil {
+		t.Fatalf("could not create sink: %v", sinkErr)
+	}
+
+	scores := []LeaderboardScore{
+		{Rank: 1, Address: "0xa", Score: 20, PointsData: map[string]interface{}{"adventurer_id": 1}},
+		{Rank: 2, Address: "0xb", Score: 10, PointsData: map[string]interface{}{}},
+	}
+	if pushErr := sink.Push("first", scores, true); pushErr != nil {
+		t.Fatalf("could not push leaderboard: %v", pushErr)
+	}
+	if pushErr := sink.Push("second", scores[1:], true); pushErr != nil {
+		t.Fatalf("could not push leaderboard: %v", pushErr)
+	}
+	if closeErr := sink.Close(); closeErr != nil {
+		t.Fatalf("could not close sink: %v", closeErr)
+	}
+
+	ifp, openErr := os.Open(csvFile)
+	if openErr != nil {
+		t.Fatalf("could not open CSV file: %v", openErr)
+	}
+	defer ifp.Close()
+	rows, readErr := csv.NewReader(ifp).ReadAll()
+	if readErr != nil {
+		t.Fatalf("could not read CSV file: %v", readErr)
+	}
+
+	expected := [][]string{
+		CSV_SINK_HEADER,
+		{"first", "1", "0xa", "20", `{"adventurer_id":1}`},
+		{"first", "2", "0xb", "10", "{}"},
+		{"second", "2", "0xb", "10", "{}"},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("expected rows %v, got %v", expected, rows)
+	}
+}
+
+func TestCSVSinkToStdoutRequiresOutfile(t *testing.T) {
+	cmd := CreateLeaderboardsCmd()
+	cmd.SetArgs([]string{"total", "--push", "--sink", SINK_CSV, "--sink-target", "-", "-l", "leaderboard-id"})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	executeErr := cmd.Execute()
+	if executeErr == nil || !strings.Contains(executeErr.Error(), "--outfile") {
+		t.Fatalf("expected an error asking for --outfile, got %v", executeErr)
+	}
+}
+
+// sqlSinkRows returns the rank, address and score of each row of the leaderboard in the SQL sink's table,
+// ordered by rank.
+func sqlSinkRows(t *testing.T, sink *SQLSink, leaderboardID string) []LeaderboardScore {
+	rows, queryErr := sink.DB.Query("SELECT rank, address, score FROM leaderboard_scores WHERE leaderboard_id = ? ORDER BY rank, address", leaderboardID)
+	if queryErr != nil {
+		t.Fatalf("could not query leaderboard_scores: %v", queryErr)
+	}
+	defer rows.Close()
+
+	result := []LeaderboardScore{}
+	for rows.Next() {
+		var score LeaderboardScore
+		if scanErr := rows.Scan(&score.Rank, &score.Address, &score.Score); scanErr != nil {
+			t.Fatalf("could not scan row: %v", scanErr)
+		}
+		result = append(result, score)
+	}
+	return result
+}
+
+func TestSQLSink(t *testing.T) {
+	sink, sinkErr := NewSQLSink(filepath.Join(t.TempDir(), "leaderboards.db"))
+	if sinkErr != nil {
+		t.Fatalf("could not create sink: %v", sinkErr)
+	}
+	defer sink.Close()
+
+	first := []LeaderboardScore{
+		{Rank: 1, Address: "0xa", Score: 20, PointsData: map[string]interface{}{}},
+		{Rank: 2, Address: "0xb", Score: 10, PointsData: map[string]interface{}{}},
+	}
+	if pushErr := sink.Push("leaderboard", first, true); pushErr != nil {
+		t.Fatalf("could not push leaderboard: %v", pushErr)
+	}
+	if pushErr := sink.Push("other", first[:1], true); pushErr != nil {
+		t.Fatalf("could not push leaderboard: %v", pushErr)
+	}
+
+	// Without overwrite, scores are upserted and entrants who are not in the leaderboard are kept.
+	update := []LeaderboardScore{
+		{Rank: 1, Address: "0xb", Score: 30, PointsData: map[string]interface{}{}},
+		{Rank: 3, Address: "0xc", Score: 5, PointsData: map[string]interface{}{}},
+	}
+	if pushErr := sink.Push("leaderboard", update, false); pushErr != nil {
+		t.Fatalf("could not push leaderboard: %v", pushErr)
+	}
+	expected := []LeaderboardScore{
+		{Rank: 1, Address: "0xa", Score: 20},
+		{Rank: 1, Address: "0xb", Score: 30},
+		{Rank: 3, Address: "0xc", Score: 5},
+	}
+	if rows := sqlSinkRows(t, sink, "leaderboard"); !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("after upsert, expected %+v, got %+v", expected, rows)
+	}
+
+	// With overwrite, the leaderboard is replaced, and other leaderboards are left alone.
+	if pushErr := sink.Push("leaderboard", update[:1], true); pushErr != nil {
+		t.Fatalf("could not push leaderboard: %v", pushErr)
+	}
+	expected = []LeaderboardScore{{Rank: 1, Address: "0xb", Score: 30}}
+	if rows := sqlSinkRows(t, sink, "leaderboard"); !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("after overwrite, expected %+v, got %+v", expected, rows)
+	}
+	expected = []LeaderboardScore{{Rank: 1, Address: "0xa", Score: 20}}
+	if rows := sqlSinkRows(t, sink, "other"); !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("other leaderboard: expected %+v, got %+v", expected, rows)
+	}
+}
+
+func TestSQLSinkRebind(t *testing.T) {
+	sink := &SQLSink{Postgres: true}
+	rebound := sink.rebind("INSERT INTO t (a, b) VALUES (?, ?)")
+	if rebound != "INSERT INTO t (a, b) VALUES ($1, $2)" {
+		t.Fatalf("unexpected rebound statement: %s", rebound)
+	}
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+var ErrEventWithoutPosition error = errors.New("event does not specify its block hash, transaction hash and emitting contract (was it crawled by an older version of this tool?)")
+
+// Schema of the event store. Each event is stored as the line that "stark events" writes for it, alongside
+// the columns by which events can be looked up. The id column records the order in which the events were
+// crawled, which is also their order within each block.
+var EVENT_STORE_SCHEMA = `
+CREATE TABLE IF NOT EXISTS events (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	block_number INTEGER NOT NULL,
+	block_hash TEXT NOT NULL,
+	transaction_hash TEXT NOT NULL,
+	from_address TEXT NOT NULL,
+	event_index INTEGER NOT NULL,
+	adventurer_id TEXT,
+	owner TEXT,
+	event TEXT NOT NULL,
+	UNIQUE (transaction_hash, from_address, event_index)
+);
+CREATE INDEX IF NOT EXISTS events_name ON events (name, block_number);
+CREATE INDEX IF NOT EXISTS events_adventurer_id ON events (adventurer_id, block_number);
+CREATE INDEX IF NOT EXISTS events_owner ON events (owner, block_number);
+CREATE INDEX IF NOT EXISTS events_block_number ON events (block_number);
+`
+
+// EventStore is an on-disk store of crawled events, backed by a SQLite database. Events are deduplicated by
+// transaction hash, emitting contract and event index (see IndexedEvent), so the same events may safely be
+// written to the store more than once, even by crawls of different sets of contracts. The database is
+// opened in WAL mode, so that events can be read from the store while a crawl is writing to it.
+type EventStore struct {
+	DB *sql.DB
+}
+
+// EventQuery selects events from an EventStore. Empty fields do not constrain the query.
+type EventQuery struct {
+	Names        []string
+	AdventurerID string
+	Owner        string
+	FromBlock    uint64
+	// If ToBlock is 0, there is no upper bound on the block number.
+	ToBlock uint64
+}
+
+// Opens the event store at the given path, creating it if it does not exist.
+func OpenEventStore(storePath string) (*EventStore, error) {
+	db, openErr := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=journal_mode(WAL)&_pragma=busy_timeout(60000)", storePath))
+	if openErr != nil {
+		return nil, openErr
+	}
+
+	_, schemaErr := db.Exec(EVENT_STORE_SCHEMA)
+	if schemaErr != nil {
+		db.Close()
+		return nil, schemaErr
+	}
+
+	return &EventStore{DB: db}, nil
+}
+
+func (store *EventStore) Close() error {
+	return store.DB.Close()
+}
+
+// Normalizes an adventurer ID (given in decimal or as a 0x-prefixed hex string) to the decimal form under
+// which it is stored. This is also the form in which the leaderboards identify adventurers.
+func NormalizeAdventurerID(adventurerID string) (string, error) {
+	value, ok := big.NewInt(0).SetString(adventurerID, 0)
+	if !ok {
+		return "", fmt.Errorf("invalid adventurer ID: %s", adventurerID)
+	}
+	return value.String(), nil
+}
+
+// Normalizes an address to the 0x-prefixed hex form (without leading zeros) under which it is stored.
+func NormalizeAddress(address string) (string, error) {
+	if !strings.HasPrefix(address, "0x") {
+		address = "0x" + address
+	}
+	value, ok := big.NewInt(0).SetString(address, 0)
+	if !ok {
+		return "", fmt.Errorf("invalid address: %s", address)
+	}
+	return fmt.Sprintf("0x%x", value), nil
+}
+
+// Returns the ID and owner of the adventurer that a parsed event concerns. These are taken from the
+// first "AdventurerId" and "Owner" fields found in the event, searching the event's fields level by level.
+// Returns empty strings for values that the event does not specify.
+func EventSubjects(event json.RawMessage) (string, string) {
+	var adventurerID, owner string
+
+	var decoded interface{}
+	unmarshalErr := json.Unmarshal(event, &decoded)
+	if unmarshalErr != nil {
+		return "", ""
+	}
+
+	level := []interface{}{decoded}
+	for len(level) > 0 && (adventurerID == "" || owner == "") {
+		var nextLevel []interface{}
+		for _, value := range level {
+			fields, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if adventurerID == "" {
+				if rawAdventurerID, ok := fields["AdventurerId"].(string); ok {
+					adventurerID, _ = NormalizeAdventurerID(rawAdventurerID)
+				}
+			}
+			if owner == "" {
+				if rawOwner, ok := fields["Owner"].(string); ok {
+					owner, _ = NormalizeAddress(rawOwner)
+				}
+			}
+
+			fieldNames := make([]string, 0, len(fields))
+			for fieldName := range fields {
+				fieldNames = append(fieldNames, fieldName)
+			}
+			sort.Strings(fieldNames)
+			for _, fieldName := range fieldNames {
+				nextLevel = append(nextLevel, fields[fieldName])
+			}
+		}
+		level = nextLevel
+	}
+
+	return adventurerID, owner
+}
+
+// Writes lines in the format produced by "stark events" to the store, in a single transaction. Events
+// which are already in the store are skipped. Retraction records remove the events they refer to from the
+// store. Returns the net number of events that were added to the store.
+func (store *EventStore) Write(lines [][]byte) (int, error) {
+	tx, txErr := store.DB.Begin()
+	if txErr != nil {
+		return 0, txErr
+	}
+	defer tx.Rollback()
+
+	insertStmt, insertPrepareErr := tx.Prepare(`INSERT INTO events (name, block_number, block_hash, transaction_hash, from_address, event_index, adventurer_id, owner, event)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (transaction_hash, from_address, event_index) DO NOTHING`)
+	if insertPrepareErr != nil {
+		return 0, insertPrepareErr
+	}
+	defer insertStmt.Close()
+
+	deleteStmt, deletePrepareErr := tx.Prepare("DELETE FROM events WHERE transaction_hash = ? AND from_address = ? AND event_index = ? AND block_hash = ?")
+	if deletePrepareErr != nil {
+		return 0, deletePrepareErr
+	}
+	defer deleteStmt.Close()
+
+	inserted := 0
+	for _, line := range lines {
+		var event PartialCrawledEvent
+		unmarshalErr := json.Unmarshal(line, &event)
+		if unmarshalErr != nil {
+			return 0, unmarshalErr
+		}
+		if event.BlockHash == nil || event.TransactionHash == nil || event.FromAddress == nil {
+			return 0, ErrEventWithoutPosition
+		}
+
+		if event.Name == EVENT_RETRACTED {
+			result, deleteErr := deleteStmt.Exec(event.TransactionHash.String(), event.FromAddress.String(), event.EventIndex, event.BlockHash.String())
+			if deleteErr != nil {
+				return 0, deleteErr
+			}
+			rowsAffected, rowsErr := result.RowsAffected()
+			if rowsErr != nil {
+				return 0, rowsErr
+			}
+			inserted -= int(rowsAffected)
+			continue
+		}
+
+		var adventurerID, owner sql.NullString
+		if event.Name != EVENT_UNKNOWN {
+			adventurerID.String, owner.String = EventSubjects(event.Event)
+			adventurerID.Valid = adventurerID.String != ""
+			owner.Valid = owner.String != ""
+		}
+
+		result, insertErr := insertStmt.Exec(event.Name, event.BlockNumber, event.BlockHash.String(), event.TransactionHash.String(), event.FromAddress.String(), event.EventIndex, adventurerID, owner, string(line))
+		if insertErr != nil {
+			return 0, insertErr
+		}
+		rowsAffected, rowsErr := result.RowsAffected()
+		if rowsErr != nil {
+			return 0, rowsErr
+		}
+		inserted += int(rowsAffected)
+	}
+
+	commitErr := tx.Commit()
+	if commitErr != nil {
+		return 0, commitErr
+	}
+	return inserted, nil
+}
+
+// Reads lines in the format produced by "stark events" and writes them to the store, batchSize lines
+// per transaction. Returns the net number of events that were added to the store.
+func (store *EventStore) Import(eventsFile io.Reader, batchSize int) (int, error) {
+	inserted := 0
+	var batch [][]byte
+
+	flush := func() error {
+		batchInserted, writeErr := store.Write(batch)
+		if writeErr != nil {
+			return writeErr
+		}
+		inserted += batchInserted
+		batch = nil
+		return nil
+	}
+
+	scanner := bufio.NewScanner(eventsFile)
+	for scanner.Scan() {
+		batch = append(batch, append([]byte(nil), scanner.Bytes()...))
+		if len(batch) >= batchSize {
+			flushErr := flush()
+			if flushErr != nil {
+				return inserted, flushErr
+			}
+		}
+	}
+	scanErr := scanner.Err()
+	if scanErr != nil {
+		return inserted, scanErr
+	}
+
+	if len(batch) > 0 {
+		flushErr := flush()
+		if flushErr != nil {
+			return inserted, flushErr
+		}
+	}
+	return inserted, nil
+}
+
+// Writes the events in the store which match the query to the given writer, one per line, in the order in
+// which they were emitted on the blockchain.
+func (store *EventStore) Export(query EventQuery, w io.Writer) error {
+	var conditions []string
+	var args []interface{}
+
+	if len(query.Names) > 0 {
+		placeholders := make([]string, len(query.Names))
+		for i, name := range query.Names {
+			placeholders[i] = "?"
+			args = append(args, name)
+		}
+		conditions = append(conditions, fmt.Sprintf("name IN (%s)", strings.Join(placeholders, ", ")))
+	}
+	if query.AdventurerID != "" {
+		adventurerID, normalizeErr := NormalizeAdventurerID(query.AdventurerID)
+		if normalizeErr != nil {
+			return normalizeErr
+		}
+		conditions = append(conditions, "adventurer_id = ?")
+		args = append(args, adventurerID)
+	}
+	if query.Owner != "" {
+		owner, normalizeErr := NormalizeAddress(query.Owner)
+		if normalizeErr != nil {
+			return normalizeErr
+		}
+		conditions = append(conditions, "owner = ?")
+		args = append(args, owner)
+	}
+	if query.FromBlock > 0 {
+		conditions = append(conditions, "block_number >= ?")
+		args = append(args, query.FromBlock)
+	}
+	if query.ToBlock > 0 {
+		conditions = append(conditions, "block_number <= ?")
+		args = append(args, query.ToBlock)
+	}
+
+	statement := "SELECT event FROM events"
+	if len(conditions) > 0 {
+		statement += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	statement += " ORDER BY block_number, id"
+
+	rows, queryErr := store.DB.Query(statement, args...)
+	if queryErr != nil {
+		return queryErr
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line string
+		scanErr := rows.Scan(&line)
+		if scanErr != nil {
+			return scanErr
+		}
+		_, writeErr := io.WriteString(w, line+"\n")
+		if writeErr != nil {
+			return writeErr
+		}
+	}
+	return rows.Err()
+}
+
+// Returns a reader over the events in the store which match the query, in the format produced by
+// "stark events".
+func (store *EventStore) Reader(query EventQuery) io.Reader {
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(store.Export(query, writer))
+	}()
+	return reader
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// Returns the lines that "stark events" writes for the given events, indexed as a single crawl would
+// index them.
+func crawledLines(t *testing.T, events []RawEvent) [][]byte {
+	t.Helper()
+	var cursor CrawlCursor
+	lines := make([][]byte, len(events))
+	for i, event := range events {
+		line, marshalErr := json.Marshal(UnparsedCrawledEvent(event, cursor.recordEvent(event, 0)))
+		if marshalErr != nil {
+			t.Fatalf("could not marshal event: %s", marshalErr.Error())
+		}
+		lines[i] = line
+	}
+	return lines
+}
+
+func testRawEvent(blockNumber, transactionHash, fromAddress uint64) RawEvent {
+	return RawEvent{
+		BlockNumber:     blockNumber,
+		BlockHash:       new(felt.Felt).SetUint64(blockNumber * 1000),
+		TransactionHash: new(felt.Felt).SetUint64(transactionHash),
+		FromAddress:     new(felt.Felt).SetUint64(fromAddress),
+	}
+}
+
+func countStoredEvents(t *testing.T, store *EventStore) int {
+	t.Helper()
+	var count int
+	if queryErr := store.DB.QueryRow("SELECT COUNT(*) FROM events").Scan(&count); queryErr != nil {
+		t.Fatalf("could not count events: %s", queryErr.Error())
+	}
+	return count
+}
+
+func TestEventStoreDeduplicatesCrawlsOfDifferentContracts(t *testing.T) {
+	store, openErr := OpenEventStore(filepath.Join(t.TempDir(), "events.db"))
+	if openErr != nil {
+		t.Fatalf("could not open event store: %s", openErr.Error())
+	}
+	defer store.Close()
+
+	// A transaction in which the game contract (0xa) emits two events and the Beasts contract (0xb) one.
+	gameEvents := []RawEvent{testRawEvent(5, 0x55, 0xa), testRawEvent(5, 0x55, 0xa)}
+	beastsEvents := []RawEvent{testRawEvent(5, 0x55, 0xb)}
+	bothContracts := InterleaveEvents([][]RawEvent{beastsEvents, gameEvents})
+
+	crawls := []struct {
+		name     string
+		events   []RawEvent
+		inserted int
+	}{
+		{"game contract", gameEvents, 2},
+		{"Beasts contract", beastsEvents, 1},
+		{"both contracts", bothContracts, 0},
+	}
+	for _, crawl := range crawls {
+		inserted, writeErr := store.Write(crawledLines(t, crawl.events))
+		if writeErr != nil {
+			t.Fatalf("%s: could not write events: %s", crawl.name, writeErr.Error())
+		}
+		if inserted != crawl.inserted {
+			t.Errorf("%s: expected %d events to be inserted, got %d", crawl.name, crawl.inserted, inserted)
+		}
+	}
+	if count := countStoredEvents(t, store); count != 3 {
+		t.Fatalf("expected 3 events in the store, got %d", count)
+	}
+
+	// Retracting the Beasts event leaves the game events alone.
+	retraction, marshalErr := json.Marshal(RetractionRecord(RetractedEvent{
+		BlockNumber: 5,
+		BlockHash:   beastsEvents[0].BlockHash,
+		EventID:     EventID{TransactionHash: beastsEvents[0].TransactionHash, FromAddress: beastsEvents[0].FromAddress},
+	}))
+	if marshalErr != nil {
+		t.Fatalf("could not marshal retraction record: %s", marshalErr.Error())
+	}
+	removed, writeErr := store.Write([][]byte{retraction})
+	if writeErr != nil {
+		t.Fatalf("could not write retraction record: %s", writeErr.Error())
+	}
+	if removed != -1 {
+		t.Errorf("expected the retraction to remove 1 event, got %d", -removed)
+	}
+	if count := countStoredEvents(t, store); count != 2 {
+		t.Fatalf("expected 2 events in the store after the retraction, got %d", count)
+	}
+}
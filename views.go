@@ -0,0 +1,81 @@
+package main
+
+//go:generate go run ./gencaller -abi abis/LootSurvivor.json -name LootSurvivor -o caller.go
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// AdventurerSnapshot is the state of an adventurer as reported by the LootSurvivor contract's view
+// functions at a given block.
+type AdventurerSnapshot struct {
+	AdventurerId  string
+	BlockNumber   uint64
+	Adventurer    Survivor_Adventurer_Adventurer
+	Meta          Survivor_AdventurerMeta_AdventurerMetadata
+	Bag           Survivor_Bag_Bag
+	Stats         Survivor_Stats_Stats
+	ItemsOnMarket []uint64
+}
+
+// Returns the state of an adventurer, as reported by the get_adventurer, get_adventurer_meta, get_bag,
+// get_stats, and get_items_on_market view functions of the LootSurvivor contract (see LootSurvivorCaller).
+// All the views are called at the given block or, if blockNumber is 0, at the current head of the chain
+// (at the time the snapshot is started), so that they are consistent with each other.
+func SnapshotAdventurer(ctx context.Context, providers *ProviderPool, contractAddress *felt.Felt, adventurerID string, blockNumber uint64) (AdventurerSnapshot, error) {
+	snapshot := AdventurerSnapshot{BlockNumber: blockNumber}
+
+	normalizedID, normalizeErr := NormalizeAdventurerID(adventurerID)
+	if normalizeErr != nil {
+		return snapshot, normalizeErr
+	}
+	snapshot.AdventurerId = normalizedID
+
+	if snapshot.BlockNumber == 0 {
+		blockNumberErr := providers.Do(ctx, func(provider *rpc.Provider) error {
+			var err error
+			snapshot.BlockNumber, err = provider.BlockNumber(ctx)
+			return err
+		})
+		if blockNumberErr != nil {
+			return snapshot, blockNumberErr
+		}
+	}
+	blockID := rpc.WithBlockNumber(snapshot.BlockNumber)
+
+	err := providers.Do(ctx, func(provider *rpc.Provider) error {
+		caller := NewLootSurvivorCaller(provider, contractAddress)
+
+		var adventurerErr, metaErr, bagErr, statsErr, marketErr error
+		snapshot.Adventurer, adventurerErr = caller.GetAdventurer(ctx, blockID, normalizedID)
+		if adventurerErr != nil {
+			return adventurerErr
+		}
+		snapshot.Meta, metaErr = caller.GetAdventurerMeta(ctx, blockID, normalizedID)
+		if metaErr != nil {
+			return metaErr
+		}
+		snapshot.Bag, bagErr = caller.GetBag(ctx, blockID, normalizedID)
+		if bagErr != nil {
+			return bagErr
+		}
+		snapshot.Stats, statsErr = caller.GetStats(ctx, blockID, normalizedID)
+		if statsErr != nil {
+			return statsErr
+		}
+		snapshot.ItemsOnMarket, marketErr = caller.GetItemsOnMarket(ctx, blockID, normalizedID)
+		if marketErr != nil {
+			return marketErr
+		}
+		return nil
+	})
+	if err != nil {
+		return snapshot, fmt.Errorf("could not read the state of adventurer %s: %w", normalizedID, err)
+	}
+
+	return snapshot, nil
+}
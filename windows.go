@@ -0,0 +1,395 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidWindow error = errors.New("a window may be bounded by block or by time on each side, but not both")
+
+// BlockWindow is an inclusive range of blocks. If ToBlock is 0, the window has no upper bound.
+type BlockWindow struct {
+	FromBlock uint64
+	ToBlock   uint64
+}
+
+func (window BlockWindow) Contains(blockNumber uint64) bool {
+	return blockNumber >= window.FromBlock && (window.ToBlock == 0 || blockNumber <= window.ToBlock)
+}
+
+// A window which contains no blocks.
+var EmptyBlockWindow BlockWindow = BlockWindow{FromBlock: 2, ToBlock: 1}
+
+// TimeWindow bounds a leaderboard by block numbers and/or timestamps. Each side of the window may be
+// bounded either by a block or by a time, but not both. Zero values leave that side unbounded.
+type TimeWindow struct {
+	FromBlock uint64
+	ToBlock   uint64
+	// Events from blocks with timestamps at or after FromTime are included.
+	FromTime time.Time
+	// Events from blocks with timestamps before ToTime are included.
+	ToTime time.Time
+}
+
+// Returns true if either side of the window is bounded by time, which means that the window can only be
+// resolved to blocks with the help of a Starknet RPC provider.
+func (window TimeWindow) NeedsTimestamps() bool {
+	return !window.FromTime.IsZero() || !window.ToTime.IsZero()
+}
+
+func (window TimeWindow) Validate() error {
+	if (window.FromBlock != 0 && !window.FromTime.IsZero()) || (window.ToBlock != 0 && !window.ToTime.IsZero()) {
+		return ErrInvalidWindow
+	}
+	return nil
+}
+
+// Parses a time given on the command line or in a seasons file, either in RFC 3339 format (e.g.
+// "2024-01-01T00:00:00Z") or as a Unix timestamp in seconds. An empty string is parsed as the zero time.
+func ParseWindowTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	unixSeconds, parseIntErr := strconv.ParseInt(value, 10, 64)
+	if parseIntErr == nil {
+		return time.Unix(unixSeconds, 0).UTC(), nil
+	}
+
+	result, parseErr := time.Parse(time.RFC3339, value)
+	if parseErr != nil {
+		return time.Time{}, fmt.Errorf("invalid time (expected RFC 3339 or Unix seconds): %s", value)
+	}
+	return result, nil
+}
+
+// BlockTimestampIndex caches the timestamps of blocks, so that resolving time windows does not need to
+// fetch the same blocks from the provider on every run.
+type BlockTimestampIndex struct {
+	Timestamps map[uint64]uint64 `json:"timestamps"`
+}
+
+// Loads a block timestamp index from the given file. Returns an empty index if the file does not exist.
+func LoadBlockTimestampIndex(indexFile string) (*BlockTimestampIndex, error) {
+	index := &BlockTimestampIndex{Timestamps: make(map[uint64]uint64)}
+
+	contents, readErr := os.ReadFile(indexFile)
+	if readErr != nil {
+		if errors.Is(readErr, os.ErrNotExist) {
+			return index, nil
+		}
+		return nil, readErr
+	}
+
+	unmarshalErr := json.Unmarshal(contents, index)
+	if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	if index.Timestamps == nil {
+		index.Timestamps = make(map[uint64]uint64)
+	}
+
+	return index, nil
+}
+
+// Saves the index to the given file, atomically (see WriteFileAtomically).
+func SaveBlockTimestampIndex(indexFile string, index *BlockTimestampIndex) error {
+	contents, marshalErr := json.Marshal(index)
+	if marshalErr != nil {
+		return marshalErr
+	}
+
+	return WriteFileAtomically(indexFile, contents)
+}
+
+// BlockTimeResolver resolves times to block numbers using the block timestamps from a pool of Starknet RPC
+// providers, caching the timestamps in an index.
+type BlockTimeResolver struct {
+	Providers *ProviderPool
+	Index     *BlockTimestampIndex
+	// The latest block at the time the resolver was first used.
+	head *uint64
+}
+
+// Returns the timestamp of the given block, from the index if possible.
+func (resolver *BlockTimeResolver) BlockTimestamp(ctx context.Context, blockNumber uint64) (uint64, error) {
+	timestamp, ok := resolver.Index.Timestamps[blockNumber]
+	if ok {
+		return timestamp, nil
+	}
+
+	blockErr := resolver.Providers.Do(ctx, func(provider *rpc.Provider) error {
+		block, err := provider.BlockWithTxHashes(ctx, rpc.BlockID{Number: &blockNumber})
+		if err != nil {
+			return err
+		}
+		acceptedBlock, ok := block.(*rpc.BlockTxHashes)
+		if !ok {
+			return ErrPendingBlock
+		}
+		timestamp = acceptedBlock.Timestamp
+		return nil
+	})
+	if blockErr != nil {
+		return 0, blockErr
+	}
+
+	resolver.Index.Timestamps[blockNumber] = timestamp
+	return timestamp, nil
+}
+
+// Returns the latest block number. This is only fetched once, so that all the windows resolved by the
+// resolver are resolved against the same chain.
+func (resolver *BlockTimeResolver) Head(ctx context.Context) (uint64, error) {
+	if resolver.head != nil {
+		return *resolver.head, nil
+	}
+
+	var head uint64
+	blockErr := resolver.Providers.Do(ctx, func(provider *rpc.Provider) error {
+		var err error
+		head, err = provider.BlockNumber(ctx)
+		return err
+	})
+	if blockErr != nil {
+		return 0, blockErr
+	}
+
+	resolver.head = &head
+	return head, nil
+}
+
+// Returns the first block whose timestamp is at or after the given time, by binary search over the block
+// timestamps (which never decrease from one block to the next). If there is no such block yet, returns
+// the block after the latest one. The search starts from the closest blocks in the index, so that the
+// provider is only asked for the latest block if none of the indexed blocks are at or after the time.
+func (resolver *BlockTimeResolver) FirstBlockAtOrAfter(ctx context.Context, t time.Time) (uint64, error) {
+	target := uint64(t.Unix())
+	if t.Unix() < 0 {
+		target = 0
+	}
+
+	// Invariant: every block before low is earlier than the target, and block high (if it is not past the
+	// head) is at or after it.
+	low, high := uint64(0), uint64(0)
+	bounded := false
+	for blockNumber, timestamp := range resolver.Index.Timestamps {
+		if timestamp < target {
+			if blockNumber+1 > low {
+				low = blockNumber + 1
+			}
+		} else if !bounded || blockNumber < high {
+			high = blockNumber
+			bounded = true
+		}
+	}
+	if !bounded {
+		head, headErr := resolver.Head(ctx)
+		if headErr != nil {
+			return 0, headErr
+		}
+		high = head + 1
+	}
+
+	for low < high {
+		middle := low + (high-low)/2
+		timestamp, timestampErr := resolver.BlockTimestamp(ctx, middle)
+		if timestampErr != nil {
+			return 0, timestampErr
+		}
+		if timestamp < target {
+			low = middle + 1
+		} else {
+			high = middle
+		}
+	}
+
+	return low, nil
+}
+
+// Resolves a window to the range of blocks it covers. A window whose upper bound is a time that no block
+// has reached yet extends to the latest block.
+func (resolver *BlockTimeResolver) Resolve(ctx context.Context, window TimeWindow) (BlockWindow, error) {
+	result := BlockWindow{FromBlock: window.FromBlock, ToBlock: window.ToBlock}
+
+	if !window.FromTime.IsZero() {
+		fromBlock, fromErr := resolver.FirstBlockAtOrAfter(ctx, window.FromTime)
+		if fromErr != nil {
+			return result, fromErr
+		}
+		result.FromBlock = fromBlock
+	}
+
+	if !window.ToTime.IsZero() {
+		endBlock, toErr := resolver.FirstBlockAtOrAfter(ctx, window.ToTime)
+		if toErr != nil {
+			return result, toErr
+		}
+		if endBlock <= 1 {
+			// The window ends before block 1, and a ToBlock of 0 would leave it unbounded. No events are
+			// crawled from the genesis block, so the window is empty.
+			return EmptyBlockWindow, nil
+		}
+		result.ToBlock = endBlock - 1
+	}
+
+	return result, nil
+}
+
+// Names of the events which are read from blocks before the start of a window, as well as from the blocks
+// within it. The leaderboards need these to name the adventurers whose events are within the window.
+var WindowContextEvents []string = []string{Event_Game_Game_StartGame}
+
+// Returns a reader over the lines of the given events file (as produced by the "stark events" command)
+// whose events are from blocks within the window, or are WindowContextEvents from blocks before it.
+func WithinBlocks(eventsFile io.Reader, window BlockWindow) io.Reader {
+	return SplitByBlocks(eventsFile, []BlockWindow{window})[0]
+}
+
+// Reads the given events file once, and returns a reader for each window over the lines whose events are
+// from blocks within that window (or are WindowContextEvents from blocks before it). The readers must be
+// consumed concurrently. Closing a reader before it has been read to the end stops any further lines from
+// being sent to it.
+func SplitByBlocks(eventsFile io.Reader, windows []BlockWindow) []*io.PipeReader {
+	readers := make([]*io.PipeReader, len(windows))
+	writers := make([]*io.PipeWriter, len(windows))
+	for i := range windows {
+		readers[i], writers[i] = io.Pipe()
+	}
+
+	go func() {
+		closeAll := func(err error) {
+			for _, writer := range writers {
+				if writer != nil {
+					writer.CloseWithError(err)
+				}
+			}
+		}
+
+		scanner := bufio.NewScanner(eventsFile)
+		for scanner.Scan() {
+			var event PartialCrawledEvent
+			unmarshalErr := json.Unmarshal(scanner.Bytes(), &event)
+			if unmarshalErr != nil {
+				closeAll(unmarshalErr)
+				return
+			}
+
+			isContextEvent := false
+			for _, name := range WindowContextEvents {
+				if event.Name == name {
+					isContextEvent = true
+				}
+			}
+
+			line := append(bytes.Clone(scanner.Bytes()), '\n')
+			for i, window := range windows {
+				if writers[i] == nil {
+					continue
+				}
+				if !window.Contains(event.BlockNumber) && !(event.BlockNumber < window.FromBlock && isContextEvent) {
+					continue
+				}
+				_, writeErr := writers[i].Write(line)
+				if writeErr != nil {
+					// The reader for this window has been closed.
+					writers[i] = nil
+				}
+			}
+		}
+
+		closeAll(scanner.Err())
+	}()
+
+	return readers
+}
+
+// Season is a window for which a leaderboard is generated. See SeasonsConfig.
+type Season struct {
+	Name      string `yaml:"name" json:"name"`
+	FromBlock uint64 `yaml:"from_block,omitempty" json:"from_block,omitempty"`
+	ToBlock   uint64 `yaml:"to_block,omitempty" json:"to_block,omitempty"`
+	FromTime  string `yaml:"from_time,omitempty" json:"from_time,omitempty"`
+	ToTime    string `yaml:"to_time,omitempty" json:"to_time,omitempty"`
+	// Moonstream leaderboard to which the season's leaderboard is pushed.
+	LeaderboardID string `yaml:"leaderboard_id,omitempty" json:"leaderboard_id,omitempty"`
+}
+
+// SeasonsConfig lists windows for which leaderboards are generated in a single pass over the events. It
+// can be loaded from a YAML or JSON file of the form:
+//
+//	seasons:
+//	  - name: week-1
+//	    from_time: 2024-01-01T00:00:00Z
+//	    to_time: 2024-01-08T00:00:00Z
+//	    leaderboard_id: 00000000-0000-0000-0000-000000000000
+//	  - name: genesis
+//	    from_block: 400000
+//	    to_block: 410000
+type SeasonsConfig struct {
+	Seasons []Season `yaml:"seasons" json:"seasons"`
+}
+
+// Returns the window of the season.
+func (season Season) Window() (TimeWindow, error) {
+	fromTime, fromErr := ParseWindowTime(season.FromTime)
+	if fromErr != nil {
+		return TimeWindow{}, fromErr
+	}
+	toTime, toErr := ParseWindowTime(season.ToTime)
+	if toErr != nil {
+		return TimeWindow{}, toErr
+	}
+
+	window := TimeWindow{FromBlock: season.FromBlock, ToBlock: season.ToBlock, FromTime: fromTime, ToTime: toTime}
+	return window, window.Validate()
+}
+
+// Loads a seasons file (in YAML or JSON format) and validates it.
+func LoadSeasonsConfig(seasonsFile string) (SeasonsConfig, error) {
+	var config SeasonsConfig
+
+	contents, readErr := os.ReadFile(seasonsFile)
+	if readErr != nil {
+		return config, readErr
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(contents))
+	decoder.KnownFields(true)
+	decodeErr := decoder.Decode(&config)
+	if decodeErr != nil {
+		return config, fmt.Errorf("could not parse seasons file %s: %w", seasonsFile, decodeErr)
+	}
+
+	if len(config.Seasons) == 0 {
+		return config, fmt.Errorf("seasons file %s does not define any seasons", seasonsFile)
+	}
+	names := make(map[string]bool)
+	for i, season := range config.Seasons {
+		if season.Name == "" {
+			return config, fmt.Errorf("season %d in %s has no name", i, seasonsFile)
+		}
+		if names[season.Name] {
+			return config, fmt.Errorf("season name %s appears more than once in %s", season.Name, seasonsFile)
+		}
+		names[season.Name] = true
+
+		_, windowErr := season.Window()
+		if windowErr != nil {
+			return config, fmt.Errorf("invalid window for season %s: %w", season.Name, windowErr)
+		}
+	}
+
+	return config, nil
+}
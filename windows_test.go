@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseWindowTime(t *testing.T) {
+	cases := []struct {
+		value    string
+		expected time.Time
+		err      bool
+	}{
+		{"", time.Time{}, false},
+		{"1704067200", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2024-01-01T00:00:00Z", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2024-01-01", time.Time{}, true},
+	}
+
+	for _, c := range cases {
+		parsed, parseErr := ParseWindowTime(c.value)
+		if (parseErr != nil) != c.err {
+			t.Errorf("%q: expected error %v, got %v", c.value, c.err, parseErr)
+			continue
+		}
+		if !parsed.Equal(c.expected) {
+			t.Errorf("%q: expected %s, got %s", c.value, c.expected, parsed)
+		}
+	}
+}
+
+func TestTimeWindowValidate(t *testing.T) {
+	now := time.Unix(100, 0)
+	cases := []struct {
+		name   string
+		window TimeWindow
+		valid  bool
+	}{
+		{"unbounded", TimeWindow{}, true},
+		{"blocks", TimeWindow{FromBlock: 5, ToBlock: 10}, true},
+		{"block and time", TimeWindow{FromBlock: 5, ToTime: now}, true},
+		{"both lower bounds", TimeWindow{FromBlock: 5, FromTime: now}, false},
+		{"both upper bounds", TimeWindow{ToBlock: 5, ToTime: now}, false},
+	}
+
+	for _, c := range cases {
+		if validateErr := c.window.Validate(); (validateErr == nil) != c.valid {
+			t.Errorf("%s: expected valid to be %v, got error %v", c.name, c.valid, validateErr)
+		}
+	}
+}
+
+func TestBlockTimeResolverResolve(t *testing.T) {
+	// The fake node's block n has timestamp n*10, and its head is block 20.
+	cases := []struct {
+		name     string
+		window   TimeWindow
+		expected BlockWindow
+	}{
+		{"blocks", TimeWindow{FromBlock: 3, ToBlock: 7}, BlockWindow{FromBlock: 3, ToBlock: 7}},
+		{"exact times", TimeWindow{FromTime: time.Unix(50, 0), ToTime: time.Unix(100, 0)}, BlockWindow{FromBlock: 5, ToBlock: 9}},
+		{"times between blocks", TimeWindow{FromTime: time.Unix(55, 0), ToTime: time.Unix(101, 0)}, BlockWindow{FromBlock: 6, ToBlock: 10}},
+		{"block and time", TimeWindow{FromBlock: 2, ToTime: time.Unix(45, 0)}, BlockWindow{FromBlock: 2, ToBlock: 4}},
+		{"ends after the head", TimeWindow{FromTime: time.Unix(150, 0), ToTime: time.Unix(1000, 0)}, BlockWindow{FromBlock: 15, ToBlock: 20}},
+		{"starts after the head", TimeWindow{FromTime: time.Unix(1000, 0)}, BlockWindow{FromBlock: 21}},
+		{"ends before block 1", TimeWindow{ToTime: time.Unix(5, 0)}, EmptyBlockWindow},
+		{"ends before block 0", TimeWindow{ToTime: time.Unix(-5, 0)}, EmptyBlockWindow},
+	}
+
+	_, providers := newFakeStarknetNode(t, 20)
+	index := &BlockTimestampIndex{Timestamps: make(map[uint64]uint64)}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// Each window is resolved both with an empty index and with the index the earlier cases built.
+			for _, resolverIndex := range []*BlockTimestampIndex{{Timestamps: make(map[uint64]uint64)}, index} {
+				resolver := BlockTimeResolver{Providers: providers, Index: resolverIndex}
+				resolved, resolveErr := resolver.Resolve(context.Background(), c.window)
+				if resolveErr != nil {
+					t.Fatalf("could not resolve window: %s", resolveErr.Error())
+				}
+				if resolved != c.expected {
+					t.Errorf("expected %+v, got %+v", c.expected, resolved)
+				}
+			}
+		})
+	}
+
+	for blockNumber, timestamp := range index.Timestamps {
+		if timestamp != blockNumber*10 {
+			t.Errorf("index records timestamp %d for block %d", timestamp, blockNumber)
+		}
+	}
+}
+
+func TestSplitByBlocks(t *testing.T) {
+	var lines [][]byte
+	for blockNumber := uint64(1); blockNumber <= 6; blockNumber++ {
+		name := Event_Game_Game_DiscoveredGold
+		if blockNumber%3 == 1 {
+			name = Event_Game_Game_StartGame
+		}
+		line, marshalErr := json.Marshal(CrawledEvent{Name: name, Event: struct{}{}, BlockNumber: blockNumber})
+		if marshalErr != nil {
+			t.Fatal(marshalErr)
+		}
+		lines = append(lines, line)
+	}
+	eventsFile := append(bytes.Join(lines, []byte("\n")), '\n')
+
+	// StartGame events (in blocks 1 and 4) are also read from blocks before each window.
+	windows := []BlockWindow{{FromBlock: 0}, {FromBlock: 3, ToBlock: 4}, {FromBlock: 5}, EmptyBlockWindow}
+	expectedBlocks := [][]uint64{{1, 2, 3, 4, 5, 6}, {1, 3, 4}, {1, 4, 5, 6}, {1}}
+
+	readers := SplitByBlocks(bytes.NewReader(eventsFile), windows)
+	results := make([][]byte, len(readers))
+	readErrs := make([]error, len(readers))
+	var wg sync.WaitGroup
+	for i, reader := range readers {
+		wg.Add(1)
+		go func(i int, reader io.Reader) {
+			defer wg.Done()
+			results[i], readErrs[i] = io.ReadAll(reader)
+		}(i, reader)
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		if readErrs[i] != nil {
+			t.Fatalf("window %d: could not read events: %s", i, readErrs[i].Error())
+		}
+		var blocks []uint64
+		for _, line := range strings.Split(strings.TrimSpace(string(result)), "\n") {
+			var event PartialCrawledEvent
+			if unmarshalErr := json.Unmarshal([]byte(line), &event); unmarshalErr != nil {
+				t.Fatal(unmarshalErr)
+			}
+			blocks = append(blocks, event.BlockNumber)
+		}
+		if len(blocks) != len(expectedBlocks[i]) {
+			t.Errorf("window %+v: expected events from blocks %v, got %v", windows[i], expectedBlocks[i], blocks)
+			continue
+		}
+		for j := range blocks {
+			if blocks[j] != expectedBlocks[i][j] {
+				t.Errorf("window %+v: expected events from blocks %v, got %v", windows[i], expectedBlocks[i], blocks)
+				break
+			}
+		}
+	}
+}
+
+func TestLoadSeasonsConfig(t *testing.T) {
+	cases := []struct {
+		name     string
+		contents string
+		// Substring of the expected error, if loading should fail.
+		err string
+	}{
+		{
+			name: "valid",
+			contents: `seasons:
+  - name: week-1
+    from_time: 2024-01-01T00:00:00Z
+    to_time: 2024-01-08T00:00:00Z
+  - name: genesis
+    from_block: 400000
+    to_block: 410000
+`,
+		},
+		{name: "no seasons", contents: "seasons: []\n", err: "does not define any seasons"},
+		{name: "unnamed season", contents: "seasons:\n  - from_block: 1\n", err: "has no name"},
+		{name: "duplicate name", contents: "seasons:\n  - name: a\n  - name: a\n", err: "appears more than once"},
+		{name: "block and time bound", contents: "seasons:\n  - name: a\n    from_block: 1\n    from_time: 2024-01-01T00:00:00Z\n", err: "invalid window"},
+		{name: "invalid time", contents: "seasons:\n  - name: a\n    to_time: next week\n", err: "invalid window"},
+		{name: "unknown field", contents: "seasons:\n  - name: a\n    from: 1\n", err: "from"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			seasonsFile := filepath.Join(t.TempDir(), "seasons.yaml")
+			if writeErr := os.WriteFile(seasonsFile, []byte(c.contents), 0644); writeErr != nil {
+				t.Fatal(writeErr)
+			}
+
+			config, loadErr := LoadSeasonsConfig(seasonsFile)
+			if c.err != "" {
+				if loadErr == nil || !strings.Contains(loadErr.Error(), c.err) {
+					t.Fatalf("expected an error containing %q, got %v", c.err, loadErr)
+				}
+				return
+			}
+			if loadErr != nil {
+				t.Fatalf("could not load seasons: %s", loadErr.Error())
+			}
+
+			window, windowErr := config.Seasons[0].Window()
+			if windowErr != nil {
+				t.Fatal(windowErr)
+			}
+			if !window.FromTime.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) || !window.ToTime.Equal(time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("unexpected window for week-1: %+v", window)
+			}
+			if config.Seasons[1].FromBlock != 400000 || config.Seasons[1].ToBlock != 410000 {
+				t.Errorf("unexpected window for genesis: %+v", config.Seasons[1])
+			}
+		})
+	}
+}